@@ -0,0 +1,121 @@
+package z3
+
+/*
+#include <z3.h>
+*/
+import "C"
+import "strings"
+
+// ConstArray creates an array over domain where every index holds value
+// Example: ConstArray(IntSort, zero) models memory that starts out all zeros
+func (ctx *Context) ConstArray(domain *Sort, value *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_const_array(ctx.c, domain.s, value.ast))
+}
+
+// ArrayDefault returns the value the array holds at all but finitely many indices
+func (ctx *Context) ArrayDefault(array *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_array_default(ctx.c, array.ast))
+}
+
+// ArrayMap applies f pointwise: result[i] = f(arrays[0][i], arrays[1][i], ...)
+func (ctx *Context) ArrayMap(f *FuncDecl, arrays ...*Expr) *Expr {
+	cArgs := make([]C.Z3_ast, len(arrays))
+	for i, arg := range arrays {
+		cArgs[i] = arg.ast
+	}
+
+	var ptr *C.Z3_ast
+	if len(cArgs) > 0 {
+		ptr = &cArgs[0]
+	}
+
+	return ctx.wrap(C.Z3_mk_map(ctx.c, f.d, C.uint(len(arrays)), ptr))
+}
+
+// ArrayExt returns an index at which l and r differ whenever l != r
+// Useful to name the witness of extensionality in custom encodings.
+func (ctx *Context) ArrayExt(l, r *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_array_ext(ctx.c, l.ast, r.ast))
+}
+
+// EvalArray evaluates an array expression in the model
+// It returns the explicitly stored entries keyed by their printed index and
+// the default value held everywhere else. Keys and values are SMT-LIB strings
+// such as "(- 1)" or "#x0a"; multi-dimensional indices are joined by ", ".
+// Arrays the model does not describe as a finite table, e.g. a lambda such
+// as (lambda ((i Int)) (+ 1 i)), report ok=false; Eval still prints them.
+func (m *Model) EvalArray(e *Expr) (entries map[string]string, def string, ok bool) {
+	res, ok := m.eval(e)
+	if !ok {
+		return nil, "", false
+	}
+
+	c := m.ctx.c
+	entries = make(map[string]string)
+	a := res.ast
+	for {
+		// Z3 may answer with a reference to an auxiliary function (as-array)
+		if bool(C.Z3_is_as_array(c, a)) {
+			return m.funcInterpEntries(C.Z3_get_as_array_func_decl(c, a), entries)
+		}
+		if C.Z3_get_ast_kind(c, a) != C.Z3_APP_AST {
+			return nil, "", false
+		}
+
+		app := C.Z3_to_app(c, a)
+		switch C.Z3_get_decl_kind(c, C.Z3_get_app_decl(c, app)) {
+		case C.Z3_OP_STORE:
+			// Outer stores shadow inner ones at the same index
+			n := C.Z3_get_app_num_args(c, app)
+			idx := make([]string, 0, n-2)
+			for i := C.uint(1); i+1 < n; i++ {
+				idx = append(idx, astString(c, C.Z3_get_app_arg(c, app, i)))
+			}
+			key := strings.Join(idx, ", ")
+			if _, seen := entries[key]; !seen {
+				entries[key] = astString(c, C.Z3_get_app_arg(c, app, n-1))
+			}
+			a = C.Z3_get_app_arg(c, app, 0)
+		case C.Z3_OP_CONST_ARRAY:
+			return entries, astString(c, C.Z3_get_app_arg(c, app, 0)), true
+		default:
+			return nil, "", false
+		}
+	}
+}
+
+// funcInterpEntries adds the finite graph of an as-array function to entries
+func (m *Model) funcInterpEntries(f C.Z3_func_decl, entries map[string]string) (map[string]string, string, bool) {
+	c := m.ctx.c
+	fi := C.Z3_model_get_func_interp(c, m.m, f)
+	if fi == nil {
+		return nil, "", false
+	}
+	C.Z3_func_interp_inc_ref(c, fi)
+	defer C.Z3_func_interp_dec_ref(c, fi)
+
+	num := C.Z3_func_interp_get_num_entries(c, fi)
+	for i := C.uint(0); i < num; i++ {
+		entry := C.Z3_func_interp_get_entry(c, fi, i)
+		C.Z3_func_entry_inc_ref(c, entry)
+
+		nargs := C.Z3_func_entry_get_num_args(c, entry)
+		idx := make([]string, nargs)
+		for j := C.uint(0); j < nargs; j++ {
+			idx[j] = astString(c, C.Z3_func_entry_get_arg(c, entry, j))
+		}
+		key := strings.Join(idx, ", ")
+		if _, seen := entries[key]; !seen {
+			entries[key] = astString(c, C.Z3_func_entry_get_value(c, entry))
+		}
+
+		C.Z3_func_entry_dec_ref(c, entry)
+	}
+
+	def := astString(c, C.Z3_func_interp_get_else(c, fi))
+	return entries, def, true
+}
+
+func astString(c C.Z3_context, a C.Z3_ast) string {
+	return C.GoString(C.Z3_ast_to_string(c, a))
+}
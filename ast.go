@@ -0,0 +1,134 @@
+package z3
+
+/*
+#include <z3.h>
+*/
+import "C"
+import "fmt"
+
+// ExprKind tells what kind of node an expression is (Z3_ast_kind)
+type ExprKind int
+
+const (
+	KindNumeral    ExprKind = C.Z3_NUMERAL_AST
+	KindApp        ExprKind = C.Z3_APP_AST
+	KindVar        ExprKind = C.Z3_VAR_AST
+	KindQuantifier ExprKind = C.Z3_QUANTIFIER_AST
+	KindSort       ExprKind = C.Z3_SORT_AST
+	KindFuncDecl   ExprKind = C.Z3_FUNC_DECL_AST
+	KindUnknown    ExprKind = C.Z3_UNKNOWN_AST
+)
+
+// Kind reports whether e is a numeral, an application, a bound variable or a quantifier
+func (e *Expr) Kind() ExprKind {
+	return ExprKind(C.Z3_get_ast_kind(e.ctx.c, e.ast))
+}
+
+// Sort returns the sort (type) of e
+func (e *Expr) Sort() *Sort {
+	return &Sort{c: e.ctx, s: C.Z3_get_sort(e.ctx.c, e.ast)}
+}
+
+// IsApp is true for applications, including constants and numerals
+func (e *Expr) IsApp() bool {
+	return bool(C.Z3_is_app(e.ctx.c, e.ast))
+}
+
+// IsConst is true for applications without arguments, e.g. variables created by Const
+func (e *Expr) IsConst() bool {
+	return e.IsApp() && e.NumArgs() == 0
+}
+
+// IsNumeral is true for numeric constants such as 42 or #x2a
+func (e *Expr) IsNumeral() bool {
+	return bool(C.Z3_is_numeral_ast(e.ctx.c, e.ast))
+}
+
+// IsTrue is true for the Boolean constant true
+func (e *Expr) IsTrue() bool {
+	return e.isOp(OpTrue)
+}
+
+// IsFalse is true for the Boolean constant false
+func (e *Expr) IsFalse() bool {
+	return e.isOp(OpFalse)
+}
+
+func (e *Expr) isOp(k DeclKind) bool {
+	d := e.Decl()
+	return d != nil && d.Kind() == k
+}
+
+// Decl returns the function applied at the root of e, or nil if e is not an application
+func (e *Expr) Decl() *FuncDecl {
+	if !e.IsApp() {
+		return nil
+	}
+	app := C.Z3_to_app(e.ctx.c, e.ast)
+	return e.ctx.wrapFuncDecl(C.Z3_get_app_decl(e.ctx.c, app))
+}
+
+// NumArgs returns the number of arguments of an application (0 otherwise)
+func (e *Expr) NumArgs() int {
+	if !e.IsApp() {
+		return 0
+	}
+	return int(C.Z3_get_app_num_args(e.ctx.c, C.Z3_to_app(e.ctx.c, e.ast)))
+}
+
+// Arg returns the i-th argument of an application
+func (e *Expr) Arg(i int) *Expr {
+	if i < 0 || i >= e.NumArgs() {
+		panic(fmt.Sprintf("z3: argument %d out of range for %s", i, e))
+	}
+	return e.ctx.wrap(C.Z3_get_app_arg(e.ctx.c, C.Z3_to_app(e.ctx.c, e.ast), C.uint(i)))
+}
+
+// Args returns all arguments of an application
+func (e *Expr) Args() []*Expr {
+	args := make([]*Expr, e.NumArgs())
+	for i := range args {
+		args[i] = e.Arg(i)
+	}
+	return args
+}
+
+// Kind returns the built-in operator implemented by the declaration
+func (f *FuncDecl) Kind() DeclKind {
+	return DeclKind(C.Z3_get_decl_kind(f.c.c, f.d))
+}
+
+// Name returns the declared name, e.g. "Age" or "+"
+func (f *FuncDecl) Name() string {
+	return symbolString(f.c.c, C.Z3_get_decl_name(f.c.c, f.d))
+}
+
+// Arity returns the number of arguments the function takes
+func (f *FuncDecl) Arity() int {
+	return int(C.Z3_get_arity(f.c.c, f.d))
+}
+
+// Domain returns the sort of the i-th argument
+func (f *FuncDecl) Domain(i int) *Sort {
+	if i < 0 || i >= f.Arity() {
+		panic(fmt.Sprintf("z3: domain index %d out of range for %s", i, f.Name()))
+	}
+	return &Sort{c: f.c, s: C.Z3_get_domain(f.c.c, f.d, C.uint(i))}
+}
+
+// Range returns the sort of the result
+func (f *FuncDecl) Range() *Sort {
+	return &Sort{c: f.c, s: C.Z3_get_range(f.c.c, f.d)}
+}
+
+func (f *FuncDecl) String() string {
+	return C.GoString(C.Z3_func_decl_to_string(f.c.c, f.d))
+}
+
+// symbolString renders a symbol the way Z3 prints it; integer symbols become k!N
+func symbolString(c C.Z3_context, s C.Z3_symbol) string {
+	if C.Z3_get_symbol_kind(c, s) == C.Z3_INT_SYMBOL {
+		return fmt.Sprintf("k!%d", int(C.Z3_get_symbol_int(c, s)))
+	}
+	return C.GoString(C.Z3_get_symbol_string(c, s))
+}
@@ -73,3 +73,67 @@ func BenchmarkQuantifierCreation(b *testing.B) {
 		_ = ctx.Forall([]*z3.Expr{x}, body)
 	}
 }
+
+// assertBVPuzzle adds a small 32-bit puzzle: three words linked by sums and orderings
+func assertBVPuzzle(ctx *z3.Context, solver *z3.Solver) {
+	bv32 := ctx.BVSort(32)
+	x := ctx.Const("x", bv32)
+	y := ctx.Const("y", bv32)
+	z := ctx.Const("z", bv32)
+
+	solver.Assert(ctx.Eq(ctx.BVAdd(x, y), ctx.BVVal(0x5eadbeef, 32)))
+	solver.Assert(ctx.Eq(ctx.BVAdd(y, z), ctx.BVVal(0x12345678, 32)))
+	solver.Assert(ctx.BVUlt(z, x))
+	solver.Assert(ctx.BVUlt(z, ctx.BVVal(0x40000000, 32)))
+	solver.Assert(ctx.Not(ctx.Eq(ctx.BVAdd(x, z), ctx.BVVal(0, 32))))
+}
+
+// benchmarkBVSolver measures building and solving the puzzle with a fresh solver per iteration
+func benchmarkBVSolver(b *testing.B, newSolver func(ctx *z3.Context) *z3.Solver) {
+	ctx := z3.NewContext(z3.NewConfig())
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		solver := newSolver(ctx)
+		assertBVPuzzle(ctx, solver)
+		if !solver.Check() {
+			b.Fatal("Expected SAT")
+		}
+	}
+}
+
+// BenchmarkBVSolverDefault uses the general purpose solver
+func BenchmarkBVSolverDefault(b *testing.B) {
+	benchmarkBVSolver(b, func(ctx *z3.Context) *z3.Solver {
+		return ctx.NewSolver()
+	})
+}
+
+// BenchmarkBVSolverForLogic uses a solver specialized for QF_BV
+func BenchmarkBVSolverForLogic(b *testing.B) {
+	benchmarkBVSolver(b, func(ctx *z3.Context) *z3.Solver {
+		return ctx.NewSolverForLogic("QF_BV")
+	})
+}
+
+// BenchmarkBVSolverSimple uses the solver without preprocessing
+func BenchmarkBVSolverSimple(b *testing.B) {
+	benchmarkBVSolver(b, func(ctx *z3.Context) *z3.Solver {
+		return ctx.NewSimpleSolver()
+	})
+}
+
+// BenchmarkBVSolverFromTactic bit-blasts to SAT through an explicit tactic pipeline
+func BenchmarkBVSolverFromTactic(b *testing.B) {
+	benchmarkBVSolver(b, func(ctx *z3.Context) *z3.Solver {
+		var tactics []*z3.Tactic
+		for _, name := range []string{"simplify", "solve-eqs", "bit-blast", "sat"} {
+			t, err := ctx.NewTactic(name)
+			if err != nil {
+				b.Fatal(err)
+			}
+			tactics = append(tactics, t)
+		}
+		return ctx.NewSolverFromTactic(tactics[0].AndThen(tactics[1:]...))
+	})
+}
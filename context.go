@@ -2,18 +2,28 @@ package z3
 
 /*
 #include <z3.h>
+
+extern void errorHandler(Z3_context c, Z3_error_code e);
 */
 import "C"
-import "runtime"
+import (
+	"errors"
+	"reflect"
+	"runtime"
+)
 
 type Context struct {
 	c C.Z3_context
+
+	// structs caches record sorts built by StructSortOf
+	structs map[reflect.Type]*StructSort
 }
 
 func NewContext(cfg *Config) *Context {
 	ctx := &Context{
 		c: C.Z3_mk_context(cfg.c),
 	}
+	C.Z3_set_error_handler(ctx.c, (*C.Z3_error_handler)(C.errorHandler))
 
 	// Clean up memory via Go's GC
 	runtime.SetFinalizer(ctx, func(c *Context) {
@@ -21,3 +31,21 @@ func NewContext(cfg *Config) *Context {
 	})
 	return ctx
 }
+
+// recoverable disables the fatal error handler until the returned func runs,
+// so that failures can be read back with lastError instead of exiting.
+// Usage: defer ctx.recoverable()()
+func (ctx *Context) recoverable() func() {
+	C.Z3_set_error_handler(ctx.c, nil)
+	return func() {
+		C.Z3_set_error_handler(ctx.c, (*C.Z3_error_handler)(C.errorHandler))
+	}
+}
+
+// lastError reports the error raised by the previous Z3 call, if any
+func (ctx *Context) lastError() error {
+	if code := C.Z3_get_error_code(ctx.c); code != C.Z3_OK {
+		return errors.New("z3: " + C.GoString(C.Z3_get_error_msg(ctx.c, code)))
+	}
+	return nil
+}
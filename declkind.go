@@ -0,0 +1,197 @@
+package z3
+
+/*
+#include <z3.h>
+*/
+import "C"
+
+// DeclKind identifies the built-in operator behind a FuncDecl (Z3_decl_kind)
+// Declarations made with CreateFuncDecl and friends report OpUninterpreted.
+type DeclKind int
+
+const (
+	// Basic
+	OpTrue     DeclKind = C.Z3_OP_TRUE
+	OpFalse    DeclKind = C.Z3_OP_FALSE
+	OpEq       DeclKind = C.Z3_OP_EQ
+	OpDistinct DeclKind = C.Z3_OP_DISTINCT
+	OpITE      DeclKind = C.Z3_OP_ITE
+	OpAnd      DeclKind = C.Z3_OP_AND
+	OpOr       DeclKind = C.Z3_OP_OR
+	OpIff      DeclKind = C.Z3_OP_IFF
+	OpXor      DeclKind = C.Z3_OP_XOR
+	OpNot      DeclKind = C.Z3_OP_NOT
+	OpImplies  DeclKind = C.Z3_OP_IMPLIES
+
+	// Arithmetic
+	OpArithNum     DeclKind = C.Z3_OP_ANUM
+	OpAlgebraicNum DeclKind = C.Z3_OP_AGNUM
+	OpLE           DeclKind = C.Z3_OP_LE
+	OpGE           DeclKind = C.Z3_OP_GE
+	OpLT           DeclKind = C.Z3_OP_LT
+	OpGT           DeclKind = C.Z3_OP_GT
+	OpAdd          DeclKind = C.Z3_OP_ADD
+	OpSub          DeclKind = C.Z3_OP_SUB
+	OpUMinus       DeclKind = C.Z3_OP_UMINUS
+	OpMul          DeclKind = C.Z3_OP_MUL
+	OpDiv          DeclKind = C.Z3_OP_DIV
+	OpIDiv         DeclKind = C.Z3_OP_IDIV
+	OpRem          DeclKind = C.Z3_OP_REM
+	OpMod          DeclKind = C.Z3_OP_MOD
+	OpToReal       DeclKind = C.Z3_OP_TO_REAL
+	OpToInt        DeclKind = C.Z3_OP_TO_INT
+	OpIsInt        DeclKind = C.Z3_OP_IS_INT
+	OpPower        DeclKind = C.Z3_OP_POWER
+
+	// Arrays and sets
+	OpStore         DeclKind = C.Z3_OP_STORE
+	OpSelect        DeclKind = C.Z3_OP_SELECT
+	OpConstArray    DeclKind = C.Z3_OP_CONST_ARRAY
+	OpArrayMap      DeclKind = C.Z3_OP_ARRAY_MAP
+	OpArrayDefault  DeclKind = C.Z3_OP_ARRAY_DEFAULT
+	OpSetUnion      DeclKind = C.Z3_OP_SET_UNION
+	OpSetIntersect  DeclKind = C.Z3_OP_SET_INTERSECT
+	OpSetDifference DeclKind = C.Z3_OP_SET_DIFFERENCE
+	OpSetComplement DeclKind = C.Z3_OP_SET_COMPLEMENT
+	OpSetSubset     DeclKind = C.Z3_OP_SET_SUBSET
+	OpAsArray       DeclKind = C.Z3_OP_AS_ARRAY
+	OpArrayExt      DeclKind = C.Z3_OP_ARRAY_EXT
+
+	// Bit-vectors
+	OpBVNum     DeclKind = C.Z3_OP_BNUM
+	OpBVNeg     DeclKind = C.Z3_OP_BNEG
+	OpBVAdd     DeclKind = C.Z3_OP_BADD
+	OpBVSub     DeclKind = C.Z3_OP_BSUB
+	OpBVMul     DeclKind = C.Z3_OP_BMUL
+	OpBVSDiv    DeclKind = C.Z3_OP_BSDIV
+	OpBVUDiv    DeclKind = C.Z3_OP_BUDIV
+	OpBVSRem    DeclKind = C.Z3_OP_BSREM
+	OpBVURem    DeclKind = C.Z3_OP_BUREM
+	OpBVSMod    DeclKind = C.Z3_OP_BSMOD
+	OpBVUle     DeclKind = C.Z3_OP_ULEQ
+	OpBVSle     DeclKind = C.Z3_OP_SLEQ
+	OpBVUge     DeclKind = C.Z3_OP_UGEQ
+	OpBVSge     DeclKind = C.Z3_OP_SGEQ
+	OpBVUlt     DeclKind = C.Z3_OP_ULT
+	OpBVSlt     DeclKind = C.Z3_OP_SLT
+	OpBVUgt     DeclKind = C.Z3_OP_UGT
+	OpBVSgt     DeclKind = C.Z3_OP_SGT
+	OpBVAnd     DeclKind = C.Z3_OP_BAND
+	OpBVOr      DeclKind = C.Z3_OP_BOR
+	OpBVNot     DeclKind = C.Z3_OP_BNOT
+	OpBVXor     DeclKind = C.Z3_OP_BXOR
+	OpBVConcat  DeclKind = C.Z3_OP_CONCAT
+	OpBVSignExt DeclKind = C.Z3_OP_SIGN_EXT
+	OpBVZeroExt DeclKind = C.Z3_OP_ZERO_EXT
+	OpBVExtract DeclKind = C.Z3_OP_EXTRACT
+	OpBVShl     DeclKind = C.Z3_OP_BSHL
+	OpBVLshr    DeclKind = C.Z3_OP_BLSHR
+	OpBVAshr    DeclKind = C.Z3_OP_BASHR
+	OpInt2BV    DeclKind = C.Z3_OP_INT2BV
+	OpBV2Int    DeclKind = C.Z3_OP_BV2INT
+
+	// Sequences, strings and regular expressions
+	OpSeqUnit      DeclKind = C.Z3_OP_SEQ_UNIT
+	OpSeqEmpty     DeclKind = C.Z3_OP_SEQ_EMPTY
+	OpSeqConcat    DeclKind = C.Z3_OP_SEQ_CONCAT
+	OpSeqPrefix    DeclKind = C.Z3_OP_SEQ_PREFIX
+	OpSeqSuffix    DeclKind = C.Z3_OP_SEQ_SUFFIX
+	OpSeqContains  DeclKind = C.Z3_OP_SEQ_CONTAINS
+	OpSeqExtract   DeclKind = C.Z3_OP_SEQ_EXTRACT
+	OpSeqReplace   DeclKind = C.Z3_OP_SEQ_REPLACE
+	OpSeqAt        DeclKind = C.Z3_OP_SEQ_AT
+	OpSeqLength    DeclKind = C.Z3_OP_SEQ_LENGTH
+	OpSeqIndex     DeclKind = C.Z3_OP_SEQ_INDEX
+	OpSeqToRe      DeclKind = C.Z3_OP_SEQ_TO_RE
+	OpSeqInRe      DeclKind = C.Z3_OP_SEQ_IN_RE
+	OpStrToInt     DeclKind = C.Z3_OP_STR_TO_INT
+	OpIntToStr     DeclKind = C.Z3_OP_INT_TO_STR
+	OpRePlus       DeclKind = C.Z3_OP_RE_PLUS
+	OpReStar       DeclKind = C.Z3_OP_RE_STAR
+	OpReOption     DeclKind = C.Z3_OP_RE_OPTION
+	OpReConcat     DeclKind = C.Z3_OP_RE_CONCAT
+	OpReUnion      DeclKind = C.Z3_OP_RE_UNION
+	OpReRange      DeclKind = C.Z3_OP_RE_RANGE
+	OpReLoop       DeclKind = C.Z3_OP_RE_LOOP
+	OpReIntersect  DeclKind = C.Z3_OP_RE_INTERSECT
+	OpReEmpty      DeclKind = C.Z3_OP_RE_EMPTY_SET
+	OpReFull       DeclKind = C.Z3_OP_RE_FULL_SET
+	OpReComplement DeclKind = C.Z3_OP_RE_COMPLEMENT
+
+	// Datatypes and pseudo-Booleans
+	OpDTConstructor DeclKind = C.Z3_OP_DT_CONSTRUCTOR
+	OpDTRecognizer  DeclKind = C.Z3_OP_DT_RECOGNISER
+	OpDTAccessor    DeclKind = C.Z3_OP_DT_ACCESSOR
+	OpAtMost        DeclKind = C.Z3_OP_PB_AT_MOST
+	OpAtLeast       DeclKind = C.Z3_OP_PB_AT_LEAST
+	OpPbLe          DeclKind = C.Z3_OP_PB_LE
+	OpPbGe          DeclKind = C.Z3_OP_PB_GE
+	OpPbEq          DeclKind = C.Z3_OP_PB_EQ
+
+	// Floating point
+	OpFPANum       DeclKind = C.Z3_OP_FPA_NUM
+	OpFPAPlusInf   DeclKind = C.Z3_OP_FPA_PLUS_INF
+	OpFPAMinusInf  DeclKind = C.Z3_OP_FPA_MINUS_INF
+	OpFPANaN       DeclKind = C.Z3_OP_FPA_NAN
+	OpFPAPlusZero  DeclKind = C.Z3_OP_FPA_PLUS_ZERO
+	OpFPAMinusZero DeclKind = C.Z3_OP_FPA_MINUS_ZERO
+	OpFPAAdd       DeclKind = C.Z3_OP_FPA_ADD
+	OpFPASub       DeclKind = C.Z3_OP_FPA_SUB
+	OpFPANeg       DeclKind = C.Z3_OP_FPA_NEG
+	OpFPAMul       DeclKind = C.Z3_OP_FPA_MUL
+	OpFPADiv       DeclKind = C.Z3_OP_FPA_DIV
+	OpFPAEq        DeclKind = C.Z3_OP_FPA_EQ
+	OpFPALt        DeclKind = C.Z3_OP_FPA_LT
+	OpFPAGt        DeclKind = C.Z3_OP_FPA_GT
+	OpFPALe        DeclKind = C.Z3_OP_FPA_LE
+	OpFPAGe        DeclKind = C.Z3_OP_FPA_GE
+	OpFPAIsNaN     DeclKind = C.Z3_OP_FPA_IS_NAN
+	OpFPAToIEEEBV  DeclKind = C.Z3_OP_FPA_TO_IEEE_BV
+
+	// Proof rules, the declarations of the nodes of a proof returned by Solver.Proof
+	OpPrUndef            DeclKind = C.Z3_OP_PR_UNDEF
+	OpPrTrue             DeclKind = C.Z3_OP_PR_TRUE
+	OpPrAsserted         DeclKind = C.Z3_OP_PR_ASSERTED
+	OpPrGoal             DeclKind = C.Z3_OP_PR_GOAL
+	OpPrModusPonens      DeclKind = C.Z3_OP_PR_MODUS_PONENS
+	OpPrReflexivity      DeclKind = C.Z3_OP_PR_REFLEXIVITY
+	OpPrSymmetry         DeclKind = C.Z3_OP_PR_SYMMETRY
+	OpPrTransitivity     DeclKind = C.Z3_OP_PR_TRANSITIVITY
+	OpPrTransitivityStar DeclKind = C.Z3_OP_PR_TRANSITIVITY_STAR
+	OpPrMonotonicity     DeclKind = C.Z3_OP_PR_MONOTONICITY
+	OpPrQuantIntro       DeclKind = C.Z3_OP_PR_QUANT_INTRO
+	OpPrBind             DeclKind = C.Z3_OP_PR_BIND
+	OpPrDistributivity   DeclKind = C.Z3_OP_PR_DISTRIBUTIVITY
+	OpPrAndElim          DeclKind = C.Z3_OP_PR_AND_ELIM
+	OpPrNotOrElim        DeclKind = C.Z3_OP_PR_NOT_OR_ELIM
+	OpPrRewrite          DeclKind = C.Z3_OP_PR_REWRITE
+	OpPrRewriteStar      DeclKind = C.Z3_OP_PR_REWRITE_STAR
+	OpPrPullQuant        DeclKind = C.Z3_OP_PR_PULL_QUANT
+	OpPrPushQuant        DeclKind = C.Z3_OP_PR_PUSH_QUANT
+	OpPrElimUnusedVars   DeclKind = C.Z3_OP_PR_ELIM_UNUSED_VARS
+	OpPrDER              DeclKind = C.Z3_OP_PR_DER
+	OpPrQuantInst        DeclKind = C.Z3_OP_PR_QUANT_INST
+	OpPrHypothesis       DeclKind = C.Z3_OP_PR_HYPOTHESIS
+	OpPrLemma            DeclKind = C.Z3_OP_PR_LEMMA
+	OpPrUnitResolution   DeclKind = C.Z3_OP_PR_UNIT_RESOLUTION
+	OpPrIffTrue          DeclKind = C.Z3_OP_PR_IFF_TRUE
+	OpPrIffFalse         DeclKind = C.Z3_OP_PR_IFF_FALSE
+	OpPrCommutativity    DeclKind = C.Z3_OP_PR_COMMUTATIVITY
+	OpPrDefAxiom         DeclKind = C.Z3_OP_PR_DEF_AXIOM
+	OpPrAssumptionAdd    DeclKind = C.Z3_OP_PR_ASSUMPTION_ADD
+	OpPrLemmaAdd         DeclKind = C.Z3_OP_PR_LEMMA_ADD
+	OpPrRedundantDel     DeclKind = C.Z3_OP_PR_REDUNDANT_DEL
+	OpPrClauseTrail      DeclKind = C.Z3_OP_PR_CLAUSE_TRAIL
+	OpPrDefIntro         DeclKind = C.Z3_OP_PR_DEF_INTRO
+	OpPrApplyDef         DeclKind = C.Z3_OP_PR_APPLY_DEF
+	OpPrIffOEq           DeclKind = C.Z3_OP_PR_IFF_OEQ
+	OpPrNNFPos           DeclKind = C.Z3_OP_PR_NNF_POS
+	OpPrNNFNeg           DeclKind = C.Z3_OP_PR_NNF_NEG
+	OpPrSkolemize        DeclKind = C.Z3_OP_PR_SKOLEMIZE
+	OpPrModusPonensOEq   DeclKind = C.Z3_OP_PR_MODUS_PONENS_OEQ
+	OpPrThLemma          DeclKind = C.Z3_OP_PR_TH_LEMMA
+	OpPrHyperResolve     DeclKind = C.Z3_OP_PR_HYPER_RESOLVE
+
+	// User symbols
+	OpUninterpreted DeclKind = C.Z3_OP_UNINTERPRETED
+)
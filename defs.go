@@ -3,11 +3,13 @@ package z3
 /*
 #cgo LDFLAGS: -lz3
 #include <z3.h>
+#include <stdlib.h>
 
 // We define the error handler here ONCE to avoid "multiple definition" errors
 extern void errorHandler(Z3_context c, Z3_error_code e);
 */
 import "C"
+import "unsafe"
 
 // Config and other globals can go here
 type Config struct {
@@ -18,6 +20,26 @@ func NewConfig() *Config {
 	return &Config{c: C.Z3_mk_config()}
 }
 
+// SetParam sets a global configuration parameter such as "timeout" or
+// "model"; it only affects contexts created afterwards
+func (cfg *Config) SetParam(name, value string) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cValue := C.CString(value)
+	defer C.free(unsafe.Pointer(cValue))
+	C.Z3_set_param_value(cfg.c, cName, cValue)
+}
+
+// SetProofs turns on proof generation, which Solver.Proof needs
+// Proof tracking slows solving down, so it is off by default.
+func (cfg *Config) SetProofs(enabled bool) {
+	if enabled {
+		cfg.SetParam("proof", "true")
+	} else {
+		cfg.SetParam("proof", "false")
+	}
+}
+
 func (cfg *Config) Close() {
 	C.Z3_del_config(cfg.c)
 }
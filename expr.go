@@ -7,6 +7,7 @@ package z3
 */
 import "C"
 import (
+	"fmt"
 	"runtime"
 	"unsafe"
 )
@@ -34,6 +35,26 @@ func (ctx *Context) Const(name string, sort *Sort) *Expr {
 	return ctx.wrap(C.Z3_mk_const(ctx.c, symbol, sort.s))
 }
 
+// FreshConst creates a variable whose name starts with prefix and is
+// guaranteed not to clash with any other constant, e.g. "aux!0"
+func (ctx *Context) FreshConst(prefix string, sort *Sort) *Expr {
+	cPrefix := C.CString(prefix)
+	defer C.free(unsafe.Pointer(cPrefix))
+	return ctx.wrap(C.Z3_mk_fresh_const(ctx.c, cPrefix, sort.s))
+}
+
+// ConstInt creates the variable named by the integer idx, which is cheaper
+// than formatting a string name for each of many auxiliary variables
+// Z3 prints it as "k!idx"; distinct indices give distinct variables.
+// Z3 only accepts indices below 2^30; ConstInt panics on larger ones.
+func (ctx *Context) ConstInt(idx uint, sort *Sort) *Expr {
+	if idx >= 1<<30 {
+		panic(fmt.Sprintf("z3: ConstInt index %d is not below 2^30", idx))
+	}
+	symbol := C.Z3_mk_int_symbol(ctx.c, C.int(idx))
+	return ctx.wrap(C.Z3_mk_const(ctx.c, symbol, sort.s))
+}
+
 // Int creates a numeral integer constant
 func (ctx *Context) Int(val int, sort *Sort) *Expr {
 	return ctx.wrap(C.Z3_mk_int(ctx.c, C.int(val), sort.s))
@@ -231,3 +252,26 @@ func (ctx *Context) Xor(l, r *Expr) *Expr {
 func (ctx *Context) Implies(l, r *Expr) *Expr {
 	return ctx.wrap(C.Z3_mk_implies(ctx.c, l.ast, r.ast))
 }
+
+// ITE is the if-then-else term: if cond then t else e
+func (ctx *Context) ITE(cond, t, e *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_ite(ctx.c, cond.ast, t.ast, e.ast))
+}
+
+// wrapVector copies the contents of a Z3 AST vector into Go Exprs
+func (ctx *Context) wrapVector(v C.Z3_ast_vector) []*Expr {
+	C.Z3_ast_vector_inc_ref(ctx.c, v)
+	defer C.Z3_ast_vector_dec_ref(ctx.c, v)
+
+	n := C.Z3_ast_vector_size(ctx.c, v)
+	exprs := make([]*Expr, n)
+	for i := C.uint(0); i < n; i++ {
+		exprs[i] = ctx.wrap(C.Z3_ast_vector_get(ctx.c, v, i))
+	}
+	return exprs
+}
+
+// String prints the expression in SMT-LIB2 syntax
+func (e *Expr) String() string {
+	return C.GoString(C.Z3_ast_to_string(e.ctx.c, e.ast))
+}
@@ -0,0 +1,107 @@
+package z3
+
+/*
+#include <z3.h>
+*/
+import "C"
+
+// Equal is true if e and other are the same term (structural equality)
+// Z3 shares identical terms, so this is a pointer comparison on the C side.
+func (e *Expr) Equal(other *Expr) bool {
+	return bool(C.Z3_is_eq_ast(e.ctx.c, e.ast, other.ast))
+}
+
+// Hash returns a structural hash of the term, stable across equal terms
+func (e *Expr) Hash() uint32 {
+	return uint32(C.Z3_get_ast_hash(e.ctx.c, e.ast))
+}
+
+// ID returns the identifier Z3 gives the term; equal terms of one context share an ID
+func (e *Expr) ID() uint32 {
+	return uint32(C.Z3_get_ast_id(e.ctx.c, e.ast))
+}
+
+// ExprMap maps terms to values by term identity rather than by Go pointer
+// All keys must belong to the same Context. The zero value is ready to use.
+type ExprMap[V any] struct {
+	m map[uint32]exprEntry[V]
+}
+
+type exprEntry[V any] struct {
+	key   *Expr // also keeps the term, and therefore its ID, alive
+	value V
+}
+
+// Set stores v under k, replacing any value stored for an equal term
+func (em *ExprMap[V]) Set(k *Expr, v V) {
+	if em.m == nil {
+		em.m = make(map[uint32]exprEntry[V])
+	}
+	em.m[k.ID()] = exprEntry[V]{key: k, value: v}
+}
+
+// Get returns the value stored for a term equal to k
+func (em *ExprMap[V]) Get(k *Expr) (V, bool) {
+	entry, ok := em.m[k.ID()]
+	return entry.value, ok
+}
+
+// Delete removes the entry for a term equal to k
+func (em *ExprMap[V]) Delete(k *Expr) {
+	delete(em.m, k.ID())
+}
+
+// Len returns the number of entries
+func (em *ExprMap[V]) Len() int {
+	return len(em.m)
+}
+
+// Range calls fn for each entry in unspecified order until fn returns false
+func (em *ExprMap[V]) Range(fn func(k *Expr, v V) bool) {
+	for _, entry := range em.m {
+		if !fn(entry.key, entry.value) {
+			return
+		}
+	}
+}
+
+// ExprSet is a set of terms compared by identity, e.g. to dedupe constraints
+// All members must belong to the same Context. The zero value is ready to use.
+type ExprSet struct {
+	m ExprMap[struct{}]
+}
+
+// Add inserts e and reports whether it was not already present
+func (es *ExprSet) Add(e *Expr) bool {
+	if es.Contains(e) {
+		return false
+	}
+	es.m.Set(e, struct{}{})
+	return true
+}
+
+// Contains reports whether a term equal to e is in the set
+func (es *ExprSet) Contains(e *Expr) bool {
+	_, ok := es.m.Get(e)
+	return ok
+}
+
+// Remove deletes e from the set
+func (es *ExprSet) Remove(e *Expr) {
+	es.m.Delete(e)
+}
+
+// Len returns the number of distinct terms in the set
+func (es *ExprSet) Len() int {
+	return es.m.Len()
+}
+
+// Items returns the members in unspecified order
+func (es *ExprSet) Items() []*Expr {
+	items := make([]*Expr, 0, es.m.Len())
+	es.m.Range(func(k *Expr, _ struct{}) bool {
+		items = append(items, k)
+		return true
+	})
+	return items
+}
@@ -34,7 +34,31 @@ func (ctx *Context) CreateFuncDecl(name string, domain []*Sort, rangeSort *Sort)
 	}
 
 	d := C.Z3_mk_func_decl(ctx.c, symbol, C.uint(len(domain)), domPtr, rangeSort.s)
+	return ctx.wrapFuncDecl(d)
+}
+
+// FreshFuncDecl declares a function whose name starts with prefix and is
+// guaranteed not to clash with any other declaration
+func (ctx *Context) FreshFuncDecl(prefix string, domain []*Sort, rangeSort *Sort) *FuncDecl {
+	cPrefix := C.CString(prefix)
+	defer C.free(unsafe.Pointer(cPrefix))
+
+	cDomain := make([]C.Z3_sort, len(domain))
+	for i, s := range domain {
+		cDomain[i] = s.s
+	}
+
+	var domPtr *C.Z3_sort
+	if len(cDomain) > 0 {
+		domPtr = &cDomain[0]
+	}
+
+	d := C.Z3_mk_fresh_func_decl(ctx.c, cPrefix, C.uint(len(domain)), domPtr, rangeSort.s)
+	return ctx.wrapFuncDecl(d)
+}
 
+// wrapFuncDecl takes a reference on a declaration handed back by Z3
+func (ctx *Context) wrapFuncDecl(d C.Z3_func_decl) *FuncDecl {
 	fd := &FuncDecl{c: ctx, d: d}
 	C.Z3_inc_ref(ctx.c, C.Z3_func_decl_to_ast(ctx.c, d))
 
@@ -44,3 +68,41 @@ func (ctx *Context) CreateFuncDecl(name string, domain []*Sort, rangeSort *Sort)
 
 	return fd
 }
+
+// RecFuncDecl declares a recursive function: Name(Domain) -> Range
+// The body is supplied afterwards with AddRecDef.
+func (ctx *Context) RecFuncDecl(name string, domain []*Sort, rangeSort *Sort) *FuncDecl {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	symbol := C.Z3_mk_string_symbol(ctx.c, cName)
+
+	cDomain := make([]C.Z3_sort, len(domain))
+	for i, s := range domain {
+		cDomain[i] = s.s
+	}
+
+	var domPtr *C.Z3_sort
+	if len(cDomain) > 0 {
+		domPtr = &cDomain[0]
+	}
+
+	d := C.Z3_mk_rec_func_decl(ctx.c, symbol, C.uint(len(domain)), domPtr, rangeSort.s)
+	return ctx.wrapFuncDecl(d)
+}
+
+// AddRecDef defines f(args) = body for a function created by RecFuncDecl
+// The args are constants standing for the parameters; body may apply f again.
+// Example: ctx.AddRecDef(fib, []*Expr{n}, ctx.ITE(ctx.LT(n, two), n, ...))
+func (ctx *Context) AddRecDef(f *FuncDecl, args []*Expr, body *Expr) {
+	cArgs := make([]C.Z3_ast, len(args))
+	for i, arg := range args {
+		cArgs[i] = arg.ast
+	}
+
+	var ptr *C.Z3_ast
+	if len(cArgs) > 0 {
+		ptr = &cArgs[0]
+	}
+
+	C.Z3_add_rec_def(ctx.c, f.d, C.uint(len(args)), ptr, body.ast)
+}
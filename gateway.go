@@ -2,12 +2,38 @@ package z3
 
 /*
 #include <z3.h>
+#include <z3_version.h>
 #include <stdio.h>
+#include <stdlib.h>
+#include <stdint.h>
 
 // This is a C function that can be called by Z3
 // It can then call a Go function if we exported one
+// Like Z3's default handler it reports the error and stops the process,
+// since most wrappers cannot recover from a NULL result.
 void errorHandler(Z3_context c, Z3_error_code e) {
-    fprintf(stderr, "Z3 Error: %d\n", e);
+    fprintf(stderr, "Z3 Error: %s\n", Z3_get_error_msg(c, e));
+    exit(1);
 }
+
+// registerOnClause installs the clause callback of onclause.go on a solver
+// Z3 added clause callbacks with dependencies in 4.13; against older headers
+// it reports 0 so that Solver.OnClause can return an error instead.
+#if Z3_MAJOR_VERSION > 4 || (Z3_MAJOR_VERSION == 4 && Z3_MINOR_VERSION >= 13)
+extern void goOnClause(uintptr_t h, Z3_ast proof, unsigned n, unsigned* deps, Z3_ast_vector lits);
+
+static void onClauseHandler(void* h, Z3_ast proof, unsigned n, unsigned const* deps, Z3_ast_vector lits) {
+    goOnClause((uintptr_t)h, proof, n, (unsigned*)deps, lits);
+}
+
+int registerOnClause(Z3_context c, Z3_solver s, uintptr_t h) {
+    Z3_solver_register_on_clause(c, s, (void*)h, onClauseHandler);
+    return 1;
+}
+#else
+int registerOnClause(Z3_context c, Z3_solver s, uintptr_t h) {
+    return 0;
+}
+#endif
 */
 import "C"
@@ -17,13 +17,22 @@ func (s *Solver) GetModel() *Model {
 }
 
 func (m *Model) Eval(e *Expr) string {
+	res, ok := m.eval(e)
+	if !ok {
+		return "unknown"
+	}
+
+	// Convert the resulting AST to a string
+	return C.GoString(C.Z3_ast_to_string(m.ctx.c, res.ast))
+}
+
+// eval evaluates e with model completion and keeps the result as an Expr
+func (m *Model) eval(e *Expr) (*Expr, bool) {
 	var res C.Z3_ast
 	// C.Z3_model_eval returns a Z3_bool (which Go sees as a bool).
 	// We cast it to a Go bool to be safe and compare it to true.
 	if bool(C.Z3_model_eval(m.ctx.c, m.m, e.ast, C.bool(true), &res)) != true {
-		return "unknown"
+		return nil, false
 	}
-
-	// Convert the resulting AST to a string
-	return C.GoString(C.Z3_ast_to_string(m.ctx.c, res))
+	return m.ctx.wrap(res), true
 }
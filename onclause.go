@@ -0,0 +1,61 @@
+package z3
+
+/*
+#include <z3.h>
+#include <z3_version.h>
+#include <stdint.h>
+
+extern int registerOnClause(Z3_context c, Z3_solver s, uintptr_t h);
+*/
+import "C"
+import (
+	"fmt"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// ClauseFunc receives each clause the solver adds or infers during Check
+// proofHint names the rule that justified the clause (it may be nil), deps
+// are the ids of the clauses it was derived from and literals is the clause.
+type ClauseFunc func(proofHint *Expr, deps []uint, literals []*Expr)
+
+type onClause struct {
+	ctx *Context
+	fn  ClauseFunc
+}
+
+// OnClause streams the clauses learned and inferred by later calls to Check
+// into fn, e.g. to feed a DRAT-style checker. It needs Z3 4.13 or newer and
+// returns an error when the package was built against older headers.
+func (s *Solver) OnClause(fn ClauseFunc) error {
+	h := cgo.NewHandle(&onClause{ctx: s.ctx, fn: fn})
+	if C.registerOnClause(s.ctx.c, s.s, C.uintptr_t(h)) == 0 {
+		h.Delete()
+		return fmt.Errorf("z3: OnClause needs Z3 4.13 or newer, built against %s", C.Z3_FULL_VERSION)
+	}
+
+	// Replacing a callback releases the previous one
+	if s.onClause != 0 {
+		s.onClause.Delete()
+	}
+	s.onClause = h
+	return nil
+}
+
+//export goOnClause
+func goOnClause(h C.uintptr_t, proof C.Z3_ast, n C.uint, deps *C.uint, lits C.Z3_ast_vector) {
+	oc := cgo.Handle(h).Value().(*onClause)
+
+	var hint *Expr
+	if proof != nil {
+		hint = oc.ctx.wrap(proof)
+	}
+	ids := make([]uint, n)
+	if n > 0 {
+		for i, d := range unsafe.Slice(deps, n) {
+			ids[i] = uint(d)
+		}
+	}
+
+	oc.fn(hint, ids, oc.ctx.wrapVector(lits))
+}
@@ -0,0 +1,256 @@
+package z3
+
+// Method forms of the common operators, so that constraints read left to
+// right: x.Add(ten).Gt(twenty).And(y.Lt(x)). Each method picks the Z3
+// operator from the sort of its receiver, so one Gt serves Int, Real, BV and
+// floating point operands. The ctx.Add style functions remain available.
+
+/*
+#include <z3.h>
+*/
+import "C"
+import "fmt"
+
+// Signedness selects how bit-vector operands of comparisons and Div are read
+type Signedness int
+
+const (
+	// Signed reads bit-vectors as two's complement numbers (the default)
+	Signed Signedness = iota
+	// Unsigned reads bit-vectors as natural numbers
+	Unsigned
+)
+
+// Add returns e + other; floating point addition rounds to nearest, ties to even
+func (e *Expr) Add(other *Expr) *Expr {
+	ctx := e.ctx
+	switch e.sortKind("Add", other) {
+	case SortBV:
+		return ctx.wrap(C.Z3_mk_bvadd(ctx.c, e.ast, other.ast))
+	case SortFP:
+		return ctx.wrap(C.Z3_mk_fpa_add(ctx.c, ctx.RNE().ast, e.ast, other.ast))
+	}
+	return ctx.Add(e, other)
+}
+
+// Sub returns e - other
+func (e *Expr) Sub(other *Expr) *Expr {
+	ctx := e.ctx
+	switch e.sortKind("Sub", other) {
+	case SortBV:
+		return ctx.wrap(C.Z3_mk_bvsub(ctx.c, e.ast, other.ast))
+	case SortFP:
+		return ctx.wrap(C.Z3_mk_fpa_sub(ctx.c, ctx.RNE().ast, e.ast, other.ast))
+	}
+	return sub(e, other)
+}
+
+// Mul returns e * other
+func (e *Expr) Mul(other *Expr) *Expr {
+	ctx := e.ctx
+	switch e.sortKind("Mul", other) {
+	case SortBV:
+		return ctx.wrap(C.Z3_mk_bvmul(ctx.c, e.ast, other.ast))
+	case SortFP:
+		return ctx.wrap(C.Z3_mk_fpa_mul(ctx.c, ctx.RNE().ast, e.ast, other.ast))
+	}
+	return ctx.Mul(e, other)
+}
+
+// Div returns e / other: integer division for Int, signed division for BV
+// unless Unsigned is given
+func (e *Expr) Div(other *Expr, sign ...Signedness) *Expr {
+	ctx := e.ctx
+	switch e.sortKind("Div", other) {
+	case SortBV:
+		if unsigned(sign) {
+			return ctx.wrap(C.Z3_mk_bvudiv(ctx.c, e.ast, other.ast))
+		}
+		return ctx.wrap(C.Z3_mk_bvsdiv(ctx.c, e.ast, other.ast))
+	case SortFP:
+		return ctx.FPADiv(ctx.RNE(), e, other)
+	}
+	return ctx.wrap(C.Z3_mk_div(ctx.c, e.ast, other.ast))
+}
+
+// Neg returns -e
+func (e *Expr) Neg() *Expr {
+	ctx := e.ctx
+	switch e.sortKind("Neg", nil) {
+	case SortBV:
+		return ctx.wrap(C.Z3_mk_bvneg(ctx.c, e.ast))
+	case SortFP:
+		return ctx.FPANeg(e)
+	}
+	return ctx.wrap(C.Z3_mk_unary_minus(ctx.c, e.ast))
+}
+
+// Eq returns the formula e == other; unlike Equal it does not compare the
+// terms themselves. Floating point operands use IEEE equality, so NaN differs
+// from itself and -0 equals +0.
+func (e *Expr) Eq(other *Expr) *Expr {
+	e.checkOperand("Eq", other)
+	if e.Sort().Kind() == SortFP {
+		return e.ctx.FPAEq(e, other)
+	}
+	return e.ctx.Eq(e, other)
+}
+
+// Lt returns e < other; bit-vectors compare as signed unless Unsigned is given
+func (e *Expr) Lt(other *Expr, sign ...Signedness) *Expr {
+	ctx := e.ctx
+	switch e.sortKind("Lt", other) {
+	case SortBV:
+		if unsigned(sign) {
+			return ctx.wrap(C.Z3_mk_bvult(ctx.c, e.ast, other.ast))
+		}
+		return ctx.wrap(C.Z3_mk_bvslt(ctx.c, e.ast, other.ast))
+	case SortFP:
+		return ctx.FPALt(e, other)
+	}
+	return ctx.LT(e, other)
+}
+
+// Le returns e <= other; bit-vectors compare as signed unless Unsigned is given
+func (e *Expr) Le(other *Expr, sign ...Signedness) *Expr {
+	ctx := e.ctx
+	switch e.sortKind("Le", other) {
+	case SortBV:
+		if unsigned(sign) {
+			return ctx.wrap(C.Z3_mk_bvule(ctx.c, e.ast, other.ast))
+		}
+		return ctx.wrap(C.Z3_mk_bvsle(ctx.c, e.ast, other.ast))
+	case SortFP:
+		return ctx.wrap(C.Z3_mk_fpa_leq(ctx.c, e.ast, other.ast))
+	}
+	return ctx.wrap(C.Z3_mk_le(ctx.c, e.ast, other.ast))
+}
+
+// Gt returns e > other; bit-vectors compare as signed unless Unsigned is given
+func (e *Expr) Gt(other *Expr, sign ...Signedness) *Expr {
+	ctx := e.ctx
+	switch e.sortKind("Gt", other) {
+	case SortBV:
+		if unsigned(sign) {
+			return ctx.wrap(C.Z3_mk_bvugt(ctx.c, e.ast, other.ast))
+		}
+		return ctx.wrap(C.Z3_mk_bvsgt(ctx.c, e.ast, other.ast))
+	case SortFP:
+		return ctx.FPAGt(e, other)
+	}
+	return ctx.GT(e, other)
+}
+
+// Ge returns e >= other; bit-vectors compare as signed unless Unsigned is given
+func (e *Expr) Ge(other *Expr, sign ...Signedness) *Expr {
+	ctx := e.ctx
+	switch e.sortKind("Ge", other) {
+	case SortBV:
+		if unsigned(sign) {
+			return ctx.wrap(C.Z3_mk_bvuge(ctx.c, e.ast, other.ast))
+		}
+		return ctx.wrap(C.Z3_mk_bvsge(ctx.c, e.ast, other.ast))
+	case SortFP:
+		return ctx.wrap(C.Z3_mk_fpa_geq(ctx.c, e.ast, other.ast))
+	}
+	return ctx.wrap(C.Z3_mk_ge(ctx.c, e.ast, other.ast))
+}
+
+// And returns e && others[0] && ...
+func (e *Expr) And(others ...*Expr) *Expr {
+	e.checkBool("And", others...)
+	return e.ctx.And(append([]*Expr{e}, others...)...)
+}
+
+// Or returns e || others[0] || ...
+func (e *Expr) Or(others ...*Expr) *Expr {
+	e.checkBool("Or", others...)
+	return e.ctx.Or(append([]*Expr{e}, others...)...)
+}
+
+// Not returns !e, or the bitwise complement of a bit-vector
+func (e *Expr) Not() *Expr {
+	if e.Sort().Kind() == SortBV {
+		return e.ctx.wrap(C.Z3_mk_bvnot(e.ctx.c, e.ast))
+	}
+	e.checkBool("Not")
+	return e.ctx.Not(e)
+}
+
+// Implies returns e => other
+func (e *Expr) Implies(other *Expr) *Expr {
+	e.checkBool("Implies", other)
+	return e.ctx.Implies(e, other)
+}
+
+// Select reads the array e at index i
+func (e *Expr) Select(i *Expr) *Expr {
+	e.checkArray("Select", i, nil)
+	return e.ctx.Select(e, i)
+}
+
+// Store returns a copy of the array e with e[i] = v
+func (e *Expr) Store(i, v *Expr) *Expr {
+	e.checkArray("Store", i, v)
+	return e.ctx.Store(e, i, v)
+}
+
+// Z3 reports a sort mismatch as a fatal error that ends the process. The
+// checks below, like checkWidth and checkFormat in the typed layer and
+// Sort.mustBe, turn such mistakes into Go panics with a readable message.
+
+// sortKind returns the kind of e's sort and panics unless it is numeric and
+// other, if given, has a compatible sort
+func (e *Expr) sortKind(op string, other *Expr) SortKind {
+	k := e.Sort().Kind()
+	switch k {
+	case SortInt, SortReal, SortBV, SortFP:
+		if other != nil {
+			e.checkOperand(op, other)
+		}
+		return k
+	}
+	panic(fmt.Sprintf("z3: %s is not defined for sort %s", op, e.Sort()))
+}
+
+// checkOperand panics unless other has the sort of e; Int and Real mix, as
+// Z3 converts the Int operand
+func (e *Expr) checkOperand(op string, other *Expr) {
+	es, os := e.Sort(), other.Sort()
+	if es.Equal(os) || isArith(es.Kind()) && isArith(os.Kind()) {
+		return
+	}
+	panic(fmt.Sprintf("z3: %s got operands of sorts %s and %s", op, es, os))
+}
+
+func isArith(k SortKind) bool {
+	return k == SortInt || k == SortReal
+}
+
+// checkBool panics unless e and others are all Boolean
+func (e *Expr) checkBool(op string, others ...*Expr) {
+	for _, x := range append([]*Expr{e}, others...) {
+		if x.Sort().Kind() != SortBool {
+			panic(fmt.Sprintf("z3: %s expects Bool operands, got sort %s", op, x.Sort()))
+		}
+	}
+}
+
+// checkArray panics unless e is an array indexed by i's sort and, if v is
+// given, holding values of v's sort
+func (e *Expr) checkArray(op string, i, v *Expr) {
+	s := e.Sort()
+	if s.Kind() != SortArray {
+		panic(fmt.Sprintf("z3: %s expects an array, got sort %s", op, s))
+	}
+	if !s.ArrayDomain().Equal(i.Sort()) {
+		panic(fmt.Sprintf("z3: %s got index of sort %s for %s", op, i.Sort(), s))
+	}
+	if v != nil && !s.ArrayRange().Equal(v.Sort()) {
+		panic(fmt.Sprintf("z3: %s got value of sort %s for %s", op, v.Sort(), s))
+	}
+}
+
+func unsigned(sign []Signedness) bool {
+	return len(sign) > 0 && sign[0] == Unsigned
+}
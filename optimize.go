@@ -2,9 +2,13 @@ package z3
 
 /*
 #include <z3.h>
+#include <stdlib.h>
 */
 import "C"
-import "runtime"
+import (
+	"runtime"
+	"unsafe"
+)
 
 type Optimize struct {
 	ctx *Context
@@ -26,19 +30,138 @@ func (o *Optimize) Assert(e *Expr) {
 	C.Z3_optimize_assert(o.ctx.c, o.o, e.ast)
 }
 
+// Handle identifies the group a soft constraint was added to
+type Handle struct {
+	opt *Optimize
+	id  C.uint
+}
+
+// AssertSoft adds a constraint that may be violated at a cost of weight
+// Weights are decimal or rational strings ("1", "2.5", "3/4"). Soft constraints
+// sharing a group are minimized together as one MaxSMT objective.
+func (o *Optimize) AssertSoft(e *Expr, weight string, group string) Handle {
+	cWeight := C.CString(weight)
+	defer C.free(unsafe.Pointer(cWeight))
+	cGroup := C.CString(group)
+	defer C.free(unsafe.Pointer(cGroup))
+
+	id := C.Z3_optimize_assert_soft(o.ctx.c, o.o, e.ast, cWeight, C.Z3_mk_string_symbol(o.ctx.c, cGroup))
+	return Handle{opt: o, id: id}
+}
+
+// Penalty returns the total weight of the group's violated soft constraints
+// as found by the last Check
+func (h Handle) Penalty() string {
+	return astString(h.opt.ctx.c, C.Z3_optimize_get_lower(h.opt.ctx.c, h.opt.o, h.id))
+}
+
+// Objective is the handle Z3 returns for a Maximize or Minimize goal
+type Objective struct {
+	opt   *Optimize
+	id    C.uint
+	isMax bool
+}
+
 // Maximize adds an objective to maximize the value of an expression
-func (o *Optimize) Maximize(e *Expr) {
-	C.Z3_optimize_maximize(o.ctx.c, o.o, e.ast)
+func (o *Optimize) Maximize(e *Expr) Objective {
+	id := C.Z3_optimize_maximize(o.ctx.c, o.o, e.ast)
+	return Objective{opt: o, id: id, isMax: true}
 }
 
 // Minimize adds an objective to minimize the value of an expression
-func (o *Optimize) Minimize(e *Expr) {
-	C.Z3_optimize_minimize(o.ctx.c, o.o, e.ast)
+func (o *Optimize) Minimize(e *Expr) Objective {
+	id := C.Z3_optimize_minimize(o.ctx.c, o.o, e.ast)
+	return Objective{opt: o, id: id}
+}
+
+// Lower returns the best known lower bound of the objective after Check
+func (ob Objective) Lower() string {
+	return astString(ob.opt.ctx.c, C.Z3_optimize_get_lower(ob.opt.ctx.c, ob.opt.o, ob.id))
+}
+
+// Upper returns the best known upper bound of the objective after Check
+func (ob Objective) Upper() string {
+	return astString(ob.opt.ctx.c, C.Z3_optimize_get_upper(ob.opt.ctx.c, ob.opt.o, ob.id))
+}
+
+// Value returns the optimum found by Check: the lower bound of a maximized
+// objective, the upper bound of a minimized one
+func (ob Objective) Value() string {
+	if ob.isMax {
+		return ob.Lower()
+	}
+	return ob.Upper()
+}
+
+// Priority selects how several objectives are combined
+type Priority string
+
+const (
+	// PriorityLex optimizes objectives one after another in declaration order
+	PriorityLex Priority = "lex"
+	// PriorityPareto enumerates Pareto optimal points, one per Check
+	PriorityPareto Priority = "pareto"
+	// PriorityBox optimizes every objective independently
+	PriorityBox Priority = "box"
+)
+
+// SetPriority sets the opt.priority parameter (lexicographic by default)
+// With PriorityPareto, each call to Check yields the next point on the front
+// and returns false once the front is exhausted.
+func (o *Optimize) SetPriority(p Priority) {
+	params := o.ctx.NewParams().SetSymbol("priority", string(p))
+	C.Z3_optimize_set_params(o.ctx.c, o.o, params.p)
+}
+
+// Check solves the objectives, optionally assuming the given literals hold
+// When it fails under assumptions, UnsatCore explains which ones conflict.
+func (o *Optimize) Check(assumptions ...*Expr) bool {
+	cArgs := make([]C.Z3_ast, len(assumptions))
+	for i, arg := range assumptions {
+		cArgs[i] = arg.ast
+	}
+
+	var ptr *C.Z3_ast
+	if len(cArgs) > 0 {
+		ptr = &cArgs[0]
+	}
+
+	return int(C.Z3_optimize_check(o.ctx.c, o.o, C.uint(len(assumptions)), ptr)) == 1
+}
+
+// Push opens a scope; assertions and objectives added after it are undone by Pop
+func (o *Optimize) Push() {
+	C.Z3_optimize_push(o.ctx.c, o.o)
+}
+
+// Pop discards everything added since the matching Push
+func (o *Optimize) Pop() {
+	C.Z3_optimize_pop(o.ctx.c, o.o)
+}
+
+// UnsatCore returns the subset of assumptions that made the last Check fail
+func (o *Optimize) UnsatCore() []*Expr {
+	return o.ctx.wrapVector(C.Z3_optimize_get_unsat_core(o.ctx.c, o.o))
+}
+
+// String exports the assertions and objectives in SMT-LIB2 format
+func (o *Optimize) String() string {
+	return C.GoString(C.Z3_optimize_to_string(o.ctx.c, o.o))
+}
+
+// FromString loads SMT-LIB2 assertions and objectives into the optimizer
+func (o *Optimize) FromString(smt string) error {
+	cSmt := C.CString(smt)
+	defer C.free(unsafe.Pointer(cSmt))
+
+	defer o.ctx.recoverable()()
+	C.Z3_optimize_from_string(o.ctx.c, o.o, cSmt)
+	return o.ctx.lastError()
 }
 
-func (o *Optimize) Check() bool {
-	// 0 args for simple check
-	return int(C.Z3_optimize_check(o.ctx.c, o.o, 0, nil)) == 1
+// Statistics reports the counters of the last Check
+func (o *Optimize) Statistics() map[string]float64 {
+	return o.ctx.wrapStats(C.Z3_optimize_get_statistics(o.ctx.c, o.o))
 }
 
 func (o *Optimize) GetModel() *Model {
@@ -0,0 +1,62 @@
+package z3
+
+/*
+#include <z3.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"runtime"
+	"unsafe"
+)
+
+// Params is a set of named options for solvers, tactics and the simplifier
+type Params struct {
+	ctx *Context
+	p   C.Z3_params
+}
+
+func (ctx *Context) NewParams() *Params {
+	params := &Params{ctx: ctx, p: C.Z3_mk_params(ctx.c)}
+	C.Z3_params_inc_ref(ctx.c, params.p)
+
+	runtime.SetFinalizer(params, func(params *Params) {
+		C.Z3_params_dec_ref(params.ctx.c, params.p)
+	})
+	return params
+}
+
+// symbol interns name as a Z3 string symbol
+func (p *Params) symbol(name string) C.Z3_symbol {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	return C.Z3_mk_string_symbol(p.ctx.c, cName)
+}
+
+// SetBool sets a Boolean option, e.g. "som" for the simplifier
+func (p *Params) SetBool(name string, v bool) *Params {
+	C.Z3_params_set_bool(p.ctx.c, p.p, p.symbol(name), C.bool(v))
+	return p
+}
+
+// SetUint sets an unsigned option, e.g. "timeout"
+func (p *Params) SetUint(name string, v uint) *Params {
+	C.Z3_params_set_uint(p.ctx.c, p.p, p.symbol(name), C.uint(v))
+	return p
+}
+
+// SetDouble sets a floating point option
+func (p *Params) SetDouble(name string, v float64) *Params {
+	C.Z3_params_set_double(p.ctx.c, p.p, p.symbol(name), C.double(v))
+	return p
+}
+
+// SetSymbol sets an option whose value is a name, e.g. "priority" = "box"
+func (p *Params) SetSymbol(name string, v string) *Params {
+	C.Z3_params_set_symbol(p.ctx.c, p.p, p.symbol(name), p.symbol(v))
+	return p
+}
+
+func (p *Params) String() string {
+	return C.GoString(C.Z3_params_to_string(p.ctx.c, p.p))
+}
@@ -0,0 +1,76 @@
+package z3
+
+// Pseudo-Boolean constraints bound a (weighted) count of true literals.
+// They are much smaller than the equivalent sum of ITE(lit, 1, 0) terms.
+
+/*
+#include <z3.h>
+*/
+import "C"
+import "fmt"
+
+// AtMost is true if no more than k of lits are true
+func (ctx *Context) AtMost(k uint, lits ...*Expr) *Expr {
+	cArgs := make([]C.Z3_ast, len(lits))
+	for i, arg := range lits {
+		cArgs[i] = arg.ast
+	}
+
+	var ptr *C.Z3_ast
+	if len(cArgs) > 0 {
+		ptr = &cArgs[0]
+	}
+
+	return ctx.wrap(C.Z3_mk_atmost(ctx.c, C.uint(len(lits)), ptr, C.uint(k)))
+}
+
+// AtLeast is true if k or more of lits are true
+func (ctx *Context) AtLeast(k uint, lits ...*Expr) *Expr {
+	cArgs := make([]C.Z3_ast, len(lits))
+	for i, arg := range lits {
+		cArgs[i] = arg.ast
+	}
+
+	var ptr *C.Z3_ast
+	if len(cArgs) > 0 {
+		ptr = &cArgs[0]
+	}
+
+	return ctx.wrap(C.Z3_mk_atleast(ctx.c, C.uint(len(lits)), ptr, C.uint(k)))
+}
+
+// PbLe performs: coeffs[0]*lits[0] + coeffs[1]*lits[1] + ... <= k
+func (ctx *Context) PbLe(coeffs []int, k int, lits ...*Expr) *Expr {
+	args, cs := ctx.pbArgs(coeffs, lits)
+	return ctx.wrap(C.Z3_mk_pble(ctx.c, C.uint(len(lits)), args, cs, C.int(k)))
+}
+
+// PbGe performs: coeffs[0]*lits[0] + coeffs[1]*lits[1] + ... >= k
+func (ctx *Context) PbGe(coeffs []int, k int, lits ...*Expr) *Expr {
+	args, cs := ctx.pbArgs(coeffs, lits)
+	return ctx.wrap(C.Z3_mk_pbge(ctx.c, C.uint(len(lits)), args, cs, C.int(k)))
+}
+
+// PbEq performs: coeffs[0]*lits[0] + coeffs[1]*lits[1] + ... == k
+func (ctx *Context) PbEq(coeffs []int, k int, lits ...*Expr) *Expr {
+	args, cs := ctx.pbArgs(coeffs, lits)
+	return ctx.wrap(C.Z3_mk_pbeq(ctx.c, C.uint(len(lits)), args, cs, C.int(k)))
+}
+
+// pbArgs converts literals and their coefficients to C arrays
+func (ctx *Context) pbArgs(coeffs []int, lits []*Expr) (*C.Z3_ast, *C.int) {
+	if len(coeffs) != len(lits) {
+		panic(fmt.Sprintf("z3: %d coefficients for %d literals", len(coeffs), len(lits)))
+	}
+	if len(lits) == 0 {
+		return nil, nil
+	}
+
+	cArgs := make([]C.Z3_ast, len(lits))
+	cCoeffs := make([]C.int, len(coeffs))
+	for i, arg := range lits {
+		cArgs[i] = arg.ast
+		cCoeffs[i] = C.int(coeffs[i])
+	}
+	return &cArgs[0], &cCoeffs[0]
+}
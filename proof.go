@@ -0,0 +1,169 @@
+package z3
+
+// When proofs are enabled (Config.SetProofs) an unsatisfiable Check leaves a
+// proof of false behind. The proof is a DAG of rule applications: each node
+// derives its conclusion from the conclusions of its premises.
+
+/*
+#include <z3.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Proof returns the proof of unsatisfiability found by the last Check, or
+// nil if proofs are disabled or the last Check did not return unsat
+func (s *Solver) Proof() *Expr {
+	defer s.ctx.recoverable()()
+	p := C.Z3_solver_get_proof(s.ctx.c, s.s)
+	if s.ctx.lastError() != nil || p == nil {
+		return nil
+	}
+	return s.ctx.wrap(p)
+}
+
+// IsProofRule is true for the kinds of proof nodes (OpPrAsserted, OpPrModusPonens, ...)
+func (k DeclKind) IsProofRule() bool {
+	return k >= OpPrUndef && k <= OpPrHyperResolve
+}
+
+// ProofStep is one rule application in a proof
+type ProofStep struct {
+	ID         int // index in Proof.Steps; premises come before the steps using them
+	Rule       DeclKind
+	RuleName   string // Z3's name for the rule, e.g. "mp" or "unit-resolution"
+	Premises   []*ProofStep
+	Conclusion *Expr
+	Term       *Expr // the proof node itself
+}
+
+// Proof is a proof DAG with each shared step listed once
+type Proof struct {
+	Steps []*ProofStep // premises first; the last step is the root
+}
+
+// NewProof unfolds a proof term, such as the result of Solver.Proof, into steps
+func NewProof(proof *Expr) (*Proof, error) {
+	if proof == nil || !proof.isProof() {
+		return nil, errors.New("z3: not a proof term")
+	}
+
+	p := &Proof{}
+	ids := make(map[uint32]*ProofStep)
+
+	// Post-order with an explicit stack, as in Walk: proofs of large
+	// problems are deep enough to make recursion costly
+	type frame struct {
+		n        *Expr
+		expanded bool
+	}
+	stack := []frame{{n: proof}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if _, ok := ids[top.n.ID()]; ok {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		premises, conclusion := top.n.proofParts()
+		if !top.expanded {
+			top.expanded = true
+			for i := len(premises) - 1; i >= 0; i-- {
+				stack = append(stack, frame{n: premises[i]})
+			}
+			continue
+		}
+		n := top.n
+		stack = stack[:len(stack)-1]
+
+		decl := n.Decl()
+		step := &ProofStep{
+			ID:         len(p.Steps),
+			Rule:       decl.Kind(),
+			RuleName:   decl.Name(),
+			Premises:   make([]*ProofStep, len(premises)),
+			Conclusion: conclusion,
+			Term:       n,
+		}
+		for i, pr := range premises {
+			step.Premises[i] = ids[pr.ID()]
+		}
+		ids[n.ID()] = step
+		p.Steps = append(p.Steps, step)
+	}
+
+	return p, nil
+}
+
+// Root returns the final step, whose conclusion is false for a refutation
+func (p *Proof) Root() *ProofStep {
+	return p.Steps[len(p.Steps)-1]
+}
+
+// Walk calls fn on every step, premises before the steps that use them
+func (p *Proof) Walk(fn func(*ProofStep)) {
+	for _, s := range p.Steps {
+		fn(s)
+	}
+}
+
+// DOT renders the proof as a Graphviz digraph with edges from premises to
+// the steps derived from them
+func (p *Proof) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph proof {\n\tnode [shape=box];\n")
+	for _, s := range p.Steps {
+		label := s.RuleName + "\n" + s.Conclusion.String()
+		fmt.Fprintf(&b, "\tn%d [label=%q];\n", s.ID, label)
+		for _, pr := range s.Premises {
+			fmt.Fprintf(&b, "\tn%d -> n%d;\n", pr.ID, s.ID)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// MarshalJSON encodes the proof as a list of steps referring to their
+// premises by ID
+func (p *Proof) MarshalJSON() ([]byte, error) {
+	type jsonStep struct {
+		ID         int    `json:"id"`
+		Rule       string `json:"rule"`
+		Premises   []int  `json:"premises"`
+		Conclusion string `json:"conclusion"`
+	}
+
+	steps := make([]jsonStep, len(p.Steps))
+	for i, s := range p.Steps {
+		premises := make([]int, len(s.Premises))
+		for j, pr := range s.Premises {
+			premises[j] = pr.ID
+		}
+		steps[i] = jsonStep{ID: s.ID, Rule: s.RuleName, Premises: premises, Conclusion: s.Conclusion.String()}
+	}
+	return json.Marshal(steps)
+}
+
+func (e *Expr) isProof() bool {
+	d := e.Decl()
+	return d != nil && d.Kind().IsProofRule()
+}
+
+// proofParts splits a proof node into its premise proofs and its conclusion
+// The conclusion is the last argument; the rule for true has none.
+func (e *Expr) proofParts() (premises []*Expr, conclusion *Expr) {
+	args := e.Args()
+	if len(args) == 0 {
+		return nil, e.ctx.wrap(C.Z3_mk_true(e.ctx.c))
+	}
+	for _, a := range args[:len(args)-1] {
+		if a.isProof() {
+			premises = append(premises, a)
+		}
+	}
+	return premises, args[len(args)-1]
+}
@@ -0,0 +1,84 @@
+package z3
+
+// Quantifier elimination turns formulas such as Exists(x, body) into an
+// equivalent quantifier-free formula over the remaining free constants.
+
+/*
+#include <z3.h>
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+)
+
+// EliminateQuantifiers returns a quantifier-free formula equivalent to e
+// It runs the "qe" tactic and falls back to "qe2" when the first one fails or
+// leaves quantifiers behind, e.g. under uninterpreted functions. If neither
+// removes every quantifier it returns an error.
+func (ctx *Context) EliminateQuantifiers(e *Expr) (*Expr, error) {
+	var res *Expr
+	var err error
+	for _, name := range []string{"qe", "qe2"} {
+		res, err = ctx.applyTacticByName(name, e)
+		if err == nil && !hasQuantifier(res) {
+			return res, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("z3: could not eliminate the quantifiers of %s", res)
+}
+
+// hasQuantifier is true if a quantifier occurs anywhere in e
+func hasQuantifier(e *Expr) bool {
+	found := false
+	Walk(e, func(n *Expr) bool {
+		found = found || n.Kind() == KindQuantifier
+		return !found
+	})
+	return found
+}
+
+// applyTacticByName runs the named tactic on e and returns the disjunction of
+// the resulting subgoals, each read as the conjunction of its formulas.
+func (ctx *Context) applyTacticByName(name string, e *Expr) (*Expr, error) {
+	t, err := ctx.NewTactic(name)
+	if err != nil {
+		return nil, err
+	}
+
+	g := ctx.NewGoal(true, false, false)
+	g.Assert(e)
+	res, err := t.Apply(g)
+	if err != nil {
+		return nil, err
+	}
+	return res.AsExpr(), nil
+}
+
+// Project eliminates vars from the quantifier-free formula using the model
+// The result holds in m, is free of vars and implies Exists(vars, formula), so
+// it is a cheap under-approximation of full quantifier elimination.
+func (m *Model) Project(vars []*Expr, formula *Expr) (*Expr, error) {
+	ctx := m.ctx
+	if len(vars) == 0 {
+		return formula, nil
+	}
+
+	cVars := make([]C.Z3_app, len(vars))
+	for i, v := range vars {
+		if !bool(C.Z3_is_app(ctx.c, v.ast)) {
+			return nil, errors.New("z3: Project expects constants as variables")
+		}
+		cVars[i] = C.Z3_to_app(ctx.c, v.ast)
+	}
+
+	defer ctx.recoverable()()
+	res := C.Z3_qe_model_project(ctx.c, m.m, C.uint(len(vars)), &cVars[0], formula.ast)
+	if err := ctx.lastError(); err != nil {
+		return nil, err
+	}
+	return ctx.wrap(res), nil
+}
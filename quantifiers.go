@@ -5,10 +5,46 @@ package z3
 #include <stdlib.h>
 */
 import "C"
+import "unsafe"
+
+// QuantifierOptions tunes how Z3 instantiates a quantifier
+type QuantifierOptions struct {
+	// Weight biases instantiation; higher weights are instantiated less eagerly
+	Weight uint
+	// Patterns are the triggers (multi-patterns) for E-matching. Each inner
+	// slice is one multi-pattern whose terms must all mention the bound vars.
+	// Example: [][]*Expr{{ctx.Apply(f, x)}} instantiates on every f(t) seen
+	Patterns [][]*Expr
+	// NoPatterns are terms that must never be used as triggers
+	NoPatterns []*Expr
+	// QID names the quantifier in statistics and traces
+	QID string
+	// SkolemID names the Skolem functions created for this quantifier
+	SkolemID string
+}
 
 // Forall creates a universal quantifier: "For all vars, body is true"
 // Example: Forall([]*Expr{u}, ctx.GT(uAge, eighteen))
 func (ctx *Context) Forall(vars []*Expr, body *Expr) *Expr {
+	return ctx.ForallWith(vars, body, QuantifierOptions{})
+}
+
+// Exists creates an existential quantifier: "There exists vars such that body is true"
+func (ctx *Context) Exists(vars []*Expr, body *Expr) *Expr {
+	return ctx.ExistsWith(vars, body, QuantifierOptions{})
+}
+
+// ForallWith is Forall with explicit patterns, weight and identifiers
+func (ctx *Context) ForallWith(vars []*Expr, body *Expr, opts QuantifierOptions) *Expr {
+	return ctx.quantifier(true, vars, body, opts)
+}
+
+// ExistsWith is Exists with explicit patterns, weight and identifiers
+func (ctx *Context) ExistsWith(vars []*Expr, body *Expr, opts QuantifierOptions) *Expr {
+	return ctx.quantifier(false, vars, body, opts)
+}
+
+func (ctx *Context) quantifier(forall bool, vars []*Expr, body *Expr, opts QuantifierOptions) *Expr {
 	if len(vars) == 0 {
 		return body
 	}
@@ -19,39 +55,68 @@ func (ctx *Context) Forall(vars []*Expr, body *Expr) *Expr {
 		cVars[i] = C.Z3_to_app(ctx.c, v.ast)
 	}
 
-	// 2. Call Z3_mk_forall_const
-	// Params: context, weight, num_bound, bound_vars, num_patterns, patterns, body
-	res := C.Z3_mk_forall_const(
+	// 2. Build one Z3_pattern per multi-pattern
+	cPatterns := make([]C.Z3_pattern, len(opts.Patterns))
+	for i, terms := range opts.Patterns {
+		cTerms := make([]C.Z3_ast, len(terms))
+		for j, term := range terms {
+			cTerms[j] = term.ast
+		}
+
+		var ptr *C.Z3_ast
+		if len(cTerms) > 0 {
+			ptr = &cTerms[0]
+		}
+		cPatterns[i] = C.Z3_mk_pattern(ctx.c, C.uint(len(terms)), ptr)
+	}
+
+	cNoPatterns := make([]C.Z3_ast, len(opts.NoPatterns))
+	for i, term := range opts.NoPatterns {
+		cNoPatterns[i] = term.ast
+	}
+
+	var patPtr *C.Z3_pattern
+	if len(cPatterns) > 0 {
+		patPtr = &cPatterns[0]
+	}
+	var noPatPtr *C.Z3_ast
+	if len(cNoPatterns) > 0 {
+		noPatPtr = &cNoPatterns[0]
+	}
+
+	cQID := C.CString(opts.QID)
+	defer C.free(unsafe.Pointer(cQID))
+	cSkolemID := C.CString(opts.SkolemID)
+	defer C.free(unsafe.Pointer(cSkolemID))
+
+	// 3. Call Z3_mk_quantifier_const_ex
+	res := C.Z3_mk_quantifier_const_ex(
 		ctx.c,
-		0,                 // weight (default 0)
-		C.uint(len(vars)), // number of bound variables
-		&cVars[0],         // the variables themselves
-		0, nil,            // patterns (used for advanced E-matching, leave 0/nil for now)
+		C.bool(forall),
+		C.uint(opts.Weight),
+		C.Z3_mk_string_symbol(ctx.c, cQID),
+		C.Z3_mk_string_symbol(ctx.c, cSkolemID),
+		C.uint(len(vars)), &cVars[0], // the bound variables
+		C.uint(len(cPatterns)), patPtr, // triggers for E-matching
+		C.uint(len(cNoPatterns)), noPatPtr,
 		body.ast, // the logical body
 	)
 
 	return ctx.wrap(res)
 }
 
-// Exists creates an existential quantifier: "There exists vars such that body is true"
-func (ctx *Context) Exists(vars []*Expr, body *Expr) *Expr {
-	if len(vars) == 0 {
-		return body
-	}
-
+// Lambda creates an array from a function body: lambda vars. body
+// Example: Lambda([]*Expr{i}, ctx.Add(ctx.Select(a, i), one)) increments every cell
+func (ctx *Context) Lambda(vars []*Expr, body *Expr) *Expr {
 	cVars := make([]C.Z3_app, len(vars))
 	for i, v := range vars {
 		cVars[i] = C.Z3_to_app(ctx.c, v.ast)
 	}
 
-	res := C.Z3_mk_exists_const(
-		ctx.c,
-		0,
-		C.uint(len(vars)),
-		&cVars[0],
-		0, nil,
-		body.ast,
-	)
+	var ptr *C.Z3_app
+	if len(cVars) > 0 {
+		ptr = &cVars[0]
+	}
 
-	return ctx.wrap(res)
+	return ctx.wrap(C.Z3_mk_lambda_const(ctx.c, C.uint(len(vars)), ptr, body.ast))
 }
@@ -0,0 +1,208 @@
+package z3
+
+// Go regular expressions are lowered to Z3 regex terms over StringSort.
+// The result accepts exactly the strings regexp.MatchString would match:
+// unanchored patterns may match anywhere, so `abc` becomes .*abc.* while
+// `^abc$` stays abc.
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"unicode"
+)
+
+// RegexFromGo parses a Go regular expression and lowers it to a Z3 regex
+// Anchors are only supported at the start or end of the pattern (or of a
+// top-level alternative); (?m) line anchors and \b are rejected.
+func (ctx *Context) RegexFromGo(pattern string) (*Expr, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("z3: %v", err)
+	}
+	l := &regexLowering{ctx: ctx, pattern: pattern}
+	return l.match(re.Simplify(), false, false)
+}
+
+type regexLowering struct {
+	ctx     *Context
+	pattern string
+}
+
+// match lowers re with the "matches a substring" semantics of Go.
+// atStart/atEnd record that the surrounding pattern is already anchored.
+func (l *regexLowering) match(re *syntax.Regexp, atStart, atEnd bool) (*Expr, error) {
+	switch re.Op {
+	case syntax.OpCapture:
+		return l.match(re.Sub[0], atStart, atEnd)
+
+	case syntax.OpAlternate:
+		alts := make([]*Expr, len(re.Sub))
+		for i, sub := range re.Sub {
+			alt, err := l.match(sub, atStart, atEnd)
+			if err != nil {
+				return nil, err
+			}
+			alts[i] = alt
+		}
+		return l.ctx.ReUnion(alts...), nil
+
+	case syntax.OpBeginText, syntax.OpEndText:
+		return l.match(&syntax.Regexp{Op: syntax.OpConcat, Sub: []*syntax.Regexp{re}}, atStart, atEnd)
+
+	case syntax.OpConcat:
+		subs := re.Sub
+		for len(subs) > 0 && subs[0].Op == syntax.OpBeginText {
+			subs, atStart = subs[1:], true
+		}
+		for len(subs) > 0 && subs[len(subs)-1].Op == syntax.OpEndText {
+			subs, atEnd = subs[:len(subs)-1], true
+		}
+		switch len(subs) {
+		case 0:
+			return l.match(&syntax.Regexp{Op: syntax.OpEmptyMatch}, atStart, atEnd)
+		case 1:
+			return l.match(subs[0], atStart, atEnd)
+		}
+		re = &syntax.Regexp{Op: syntax.OpConcat, Sub: subs}
+	}
+
+	body, err := l.lower(re)
+	if err != nil {
+		return nil, err
+	}
+	parts := []*Expr{body}
+	if !atStart {
+		parts = append([]*Expr{l.ctx.ReStar(l.anyChar())}, parts...)
+	}
+	if !atEnd {
+		parts = append(parts, l.ctx.ReStar(l.anyChar()))
+	}
+	if len(parts) == 1 {
+		return body, nil
+	}
+	return l.ctx.ReConcat(parts...), nil
+}
+
+// lower translates re into a regex accepting exactly the strings re matches in full
+func (l *regexLowering) lower(re *syntax.Regexp) (*Expr, error) {
+	ctx := l.ctx
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return ctx.ReEmpty(ctx.ReSort(ctx.StringSort())), nil
+
+	case syntax.OpEmptyMatch:
+		return ctx.Re(ctx.StringVal("")), nil
+
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if err := checkChar(r); err != nil {
+				return nil, err
+			}
+		}
+		if re.Flags&syntax.FoldCase == 0 {
+			return ctx.Re(ctx.StringVal(string(re.Rune))), nil
+		}
+		chars := make([]*Expr, len(re.Rune))
+		for i, r := range re.Rune {
+			chars[i] = l.foldChar(r)
+		}
+		if len(chars) == 1 {
+			return chars[0], nil
+		}
+		return ctx.ReConcat(chars...), nil
+
+	case syntax.OpCharClass:
+		return l.class(re.Rune), nil
+
+	case syntax.OpAnyCharNotNL:
+		return l.class([]rune{0, '\n' - 1, '\n' + 1, maxChar}), nil
+
+	case syntax.OpAnyChar:
+		return l.anyChar(), nil
+
+	case syntax.OpCapture:
+		return l.lower(re.Sub[0])
+
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest:
+		sub, err := l.lower(re.Sub[0])
+		if err != nil {
+			return nil, err
+		}
+		switch re.Op {
+		case syntax.OpStar:
+			return ctx.ReStar(sub), nil
+		case syntax.OpPlus:
+			return ctx.RePlus(sub), nil
+		default:
+			return ctx.ReOption(sub), nil
+		}
+
+	case syntax.OpRepeat:
+		sub, err := l.lower(re.Sub[0])
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case re.Max == 0:
+			return ctx.Re(ctx.StringVal("")), nil
+		case re.Max < 0 && re.Min == 0:
+			return ctx.ReStar(sub), nil
+		case re.Max < 0:
+			return ctx.ReLoop(sub, uint(re.Min), 0), nil
+		default:
+			return ctx.ReLoop(sub, uint(re.Min), uint(re.Max)), nil
+		}
+
+	case syntax.OpConcat, syntax.OpAlternate:
+		subs := make([]*Expr, len(re.Sub))
+		for i, sub := range re.Sub {
+			e, err := l.lower(sub)
+			if err != nil {
+				return nil, err
+			}
+			subs[i] = e
+		}
+		if re.Op == syntax.OpConcat {
+			return ctx.ReConcat(subs...), nil
+		}
+		return ctx.ReUnion(subs...), nil
+	}
+
+	return nil, fmt.Errorf("z3: regexp %q: %v is not supported here", l.pattern, re.Op)
+}
+
+// class builds the union of the [lo, hi] rune pairs of a character class
+func (l *regexLowering) class(ranges []rune) *Expr {
+	var alts []*Expr
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo > maxChar {
+			continue
+		}
+		if hi > maxChar {
+			hi = maxChar
+		}
+		alts = append(alts, l.ctx.ReRange(l.ctx.StringVal(string(lo)), l.ctx.StringVal(string(hi))))
+	}
+
+	switch len(alts) {
+	case 0:
+		return l.ctx.ReEmpty(l.ctx.ReSort(l.ctx.StringSort()))
+	case 1:
+		return alts[0]
+	}
+	return l.ctx.ReUnion(alts...)
+}
+
+// foldChar accepts r and every rune equal to it under Unicode case folding
+func (l *regexLowering) foldChar(r rune) *Expr {
+	var orbit []rune
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		orbit = append(orbit, f, f)
+	}
+	return l.class(append([]rune{r, r}, orbit...))
+}
+
+func (l *regexLowering) anyChar() *Expr {
+	return l.class([]rune{0, maxChar})
+}
@@ -0,0 +1,101 @@
+package z3
+
+// Sets in Z3 are arrays from the element sort to Bool, so Select and Store
+// also work on them.
+
+/*
+#include <z3.h>
+*/
+import "C"
+
+// SetSort returns the sort of sets whose elements have sort elem
+func (ctx *Context) SetSort(elem *Sort) *Sort {
+	return &Sort{c: ctx, s: C.Z3_mk_set_sort(ctx.c, elem.s)}
+}
+
+// EmptySet creates the set of sort elem containing nothing
+func (ctx *Context) EmptySet(elem *Sort) *Expr {
+	return ctx.wrap(C.Z3_mk_empty_set(ctx.c, elem.s))
+}
+
+// FullSet creates the set of sort elem containing every element
+func (ctx *Context) FullSet(elem *Sort) *Expr {
+	return ctx.wrap(C.Z3_mk_full_set(ctx.c, elem.s))
+}
+
+// SetAdd returns a NEW set: set ∪ {elem}
+func (ctx *Context) SetAdd(set, elem *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_set_add(ctx.c, set.ast, elem.ast))
+}
+
+// SetDel returns a NEW set: set \ {elem}
+func (ctx *Context) SetDel(set, elem *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_set_del(ctx.c, set.ast, elem.ast))
+}
+
+// SetUnion performs: args[0] ∪ args[1] ∪ ...
+func (ctx *Context) SetUnion(args ...*Expr) *Expr {
+	cArgs := make([]C.Z3_ast, len(args))
+	for i, arg := range args {
+		cArgs[i] = arg.ast
+	}
+
+	var ptr *C.Z3_ast
+	if len(cArgs) > 0 {
+		ptr = &cArgs[0]
+	}
+
+	return ctx.wrap(C.Z3_mk_set_union(ctx.c, C.uint(len(args)), ptr))
+}
+
+// SetIntersect performs: args[0] ∩ args[1] ∩ ...
+func (ctx *Context) SetIntersect(args ...*Expr) *Expr {
+	cArgs := make([]C.Z3_ast, len(args))
+	for i, arg := range args {
+		cArgs[i] = arg.ast
+	}
+
+	var ptr *C.Z3_ast
+	if len(cArgs) > 0 {
+		ptr = &cArgs[0]
+	}
+
+	return ctx.wrap(C.Z3_mk_set_intersect(ctx.c, C.uint(len(args)), ptr))
+}
+
+// SetDifference performs: l \ r
+func (ctx *Context) SetDifference(l, r *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_set_difference(ctx.c, l.ast, r.ast))
+}
+
+// SetComplement returns every element not in set
+func (ctx *Context) SetComplement(set *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_set_complement(ctx.c, set.ast))
+}
+
+// SetMember is true if elem ∈ set
+func (ctx *Context) SetMember(elem, set *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_set_member(ctx.c, elem.ast, set.ast))
+}
+
+// SetSubset is true if l ⊆ r
+func (ctx *Context) SetSubset(l, r *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_set_subset(ctx.c, l.ast, r.ast))
+}
+
+// SetCardinality counts the members of set drawn from a known universe
+// The result is the Int sum of (u ∈ set ? 1 : 0) over universe, which must
+// hold pairwise distinct elements; it is exact whenever set ⊆ universe.
+func (ctx *Context) SetCardinality(set *Expr, universe ...*Expr) *Expr {
+	intSort := ctx.IntSort()
+	one, zero := ctx.Int(1, intSort), ctx.Int(0, intSort)
+	if len(universe) == 0 {
+		return zero
+	}
+
+	terms := make([]*Expr, len(universe))
+	for i, u := range universe {
+		terms[i] = ctx.ITE(ctx.SetMember(u, set), one, zero)
+	}
+	return ctx.Add(terms...)
+}
@@ -0,0 +1,39 @@
+package z3
+
+/*
+#include <z3.h>
+*/
+import "C"
+
+// Simplify rewrites e into an equivalent, usually smaller, expression
+// Constant subterms are folded: (+ x 1 2) becomes (+ 3 x).
+func (e *Expr) Simplify() *Expr {
+	return e.ctx.wrap(C.Z3_simplify(e.ctx.c, e.ast))
+}
+
+// SimplifyWith is Simplify controlled by the options listed in SimplifierHelp
+// Unknown options or values of the wrong type are reported as an error.
+func (e *Expr) SimplifyWith(params *Params) (*Expr, error) {
+	ctx := e.ctx
+	defer ctx.recoverable()()
+
+	descrs := C.Z3_simplify_get_param_descrs(ctx.c)
+	C.Z3_param_descrs_inc_ref(ctx.c, descrs)
+	defer C.Z3_param_descrs_dec_ref(ctx.c, descrs)
+
+	C.Z3_params_validate(ctx.c, params.p, descrs)
+	if err := ctx.lastError(); err != nil {
+		return nil, err
+	}
+
+	res := C.Z3_simplify_ex(ctx.c, e.ast, params.p)
+	if err := ctx.lastError(); err != nil {
+		return nil, err
+	}
+	return ctx.wrap(res), nil
+}
+
+// SimplifierHelp lists the options accepted by SimplifyWith
+func (ctx *Context) SimplifierHelp() string {
+	return C.GoString(C.Z3_simplify_get_help(ctx.c))
+}
@@ -2,25 +2,58 @@ package z3
 
 /*
 #include <z3.h>
+#include <stdlib.h>
 */
 import "C"
-import "runtime"
+import (
+	"runtime"
+	"runtime/cgo"
+	"unsafe"
+)
 
 type Solver struct {
-	ctx *Context
-	s   C.Z3_solver
+	ctx      *Context
+	s        C.Z3_solver
+	onClause cgo.Handle // callback installed by OnClause, if any
 }
 
 func (ctx *Context) NewSolver() *Solver {
+	return ctx.wrapSolver(C.Z3_mk_solver(ctx.c))
+}
+
+// NewSolverForLogic creates a solver specialized for an SMT-LIB logic such as
+// "QF_BV" or "QF_LIA". Assertions outside the logic may make Check fail.
+func (ctx *Context) NewSolverForLogic(logic string) *Solver {
+	cLogic := C.CString(logic)
+	defer C.free(unsafe.Pointer(cLogic))
+	return ctx.wrapSolver(C.Z3_mk_solver_for_logic(ctx.c, C.Z3_mk_string_symbol(ctx.c, cLogic)))
+}
+
+// NewSimpleSolver creates an incremental solver that skips Z3's
+// preprocessing and strategy selection
+func (ctx *Context) NewSimpleSolver() *Solver {
+	return ctx.wrapSolver(C.Z3_mk_simple_solver(ctx.c))
+}
+
+// NewSolverFromTactic creates a solver that runs the tactic pipeline on its assertions
+func (ctx *Context) NewSolverFromTactic(t *Tactic) *Solver {
+	return ctx.wrapSolver(C.Z3_mk_solver_from_tactic(ctx.c, t.t))
+}
+
+// wrapSolver takes a reference on a solver handed back by Z3
+func (ctx *Context) wrapSolver(cs C.Z3_solver) *Solver {
 	s := &Solver{
 		ctx: ctx,
-		s:   C.Z3_mk_solver(ctx.c),
+		s:   cs,
 	}
 
 	C.Z3_solver_inc_ref(ctx.c, s.s)
 
 	runtime.SetFinalizer(s, func(s *Solver) {
 		C.Z3_solver_dec_ref(s.ctx.c, s.s)
+		if s.onClause != 0 {
+			s.onClause.Delete()
+		}
 	})
 
 	return s
@@ -5,7 +5,10 @@ package z3
 #include <stdlib.h>
 */
 import "C"
-import "unsafe"
+import (
+	"fmt"
+	"unsafe"
+)
 
 type Sort struct {
 	c *Context
@@ -22,6 +25,11 @@ func (ctx *Context) IntSort() *Sort {
 	return &Sort{c: ctx, s: C.Z3_mk_int_sort(ctx.c)}
 }
 
+// RealSort returns the built-in Real (rational number) type
+func (ctx *Context) RealSort() *Sort {
+	return &Sort{c: ctx, s: C.Z3_mk_real_sort(ctx.c)}
+}
+
 // CreateSort creates a custom "Uninterpreted" sort (like 'User' or 'Profile')
 func (ctx *Context) CreateSort(name string) *Sort {
 	cName := C.CString(name)
@@ -40,3 +48,81 @@ func (ctx *Context) BVSort(bits uint) *Sort {
 func (ctx *Context) ArraySort(domain, rangeSort *Sort) *Sort {
 	return &Sort{c: ctx, s: C.Z3_mk_array_sort(ctx.c, domain.s, rangeSort.s)}
 }
+
+// SortKind classifies sorts (Z3_sort_kind)
+type SortKind int
+
+const (
+	SortUninterpreted SortKind = C.Z3_UNINTERPRETED_SORT
+	SortBool          SortKind = C.Z3_BOOL_SORT
+	SortInt           SortKind = C.Z3_INT_SORT
+	SortReal          SortKind = C.Z3_REAL_SORT
+	SortBV            SortKind = C.Z3_BV_SORT
+	SortArray         SortKind = C.Z3_ARRAY_SORT
+	SortDatatype      SortKind = C.Z3_DATATYPE_SORT
+	SortRelation      SortKind = C.Z3_RELATION_SORT
+	SortFiniteDomain  SortKind = C.Z3_FINITE_DOMAIN_SORT
+	SortFP            SortKind = C.Z3_FLOATING_POINT_SORT
+	SortRoundingMode  SortKind = C.Z3_ROUNDING_MODE_SORT
+	SortSeq           SortKind = C.Z3_SEQ_SORT // includes StringSort
+	SortRe            SortKind = C.Z3_RE_SORT
+	SortUnknown       SortKind = C.Z3_UNKNOWN_SORT
+)
+
+// Kind tells which family the sort belongs to
+// Sets are arrays into Bool and records built by StructSortOf are datatypes.
+func (s *Sort) Kind() SortKind {
+	return SortKind(C.Z3_get_sort_kind(s.c.c, s.s))
+}
+
+// Name returns the sort's name, e.g. "Int", "BitVec" or "User"
+func (s *Sort) Name() string {
+	return symbolString(s.c.c, C.Z3_get_sort_name(s.c.c, s.s))
+}
+
+// Equal is true if s and other are the same sort
+func (s *Sort) Equal(other *Sort) bool {
+	return bool(C.Z3_is_eq_sort(s.c.c, s.s, other.s))
+}
+
+func (s *Sort) String() string {
+	return C.GoString(C.Z3_sort_to_string(s.c.c, s.s))
+}
+
+// BVSize returns the number of bits of a bit-vector sort
+func (s *Sort) BVSize() uint {
+	s.mustBe(SortBV)
+	return uint(C.Z3_get_bv_sort_size(s.c.c, s.s))
+}
+
+// FPEbits returns the number of exponent bits of a floating point sort
+func (s *Sort) FPEbits() uint {
+	s.mustBe(SortFP)
+	return uint(C.Z3_fpa_get_ebits(s.c.c, s.s))
+}
+
+// FPSbits returns the number of significand bits (including the hidden bit)
+// of a floating point sort, e.g. 24 for Float32Sort
+func (s *Sort) FPSbits() uint {
+	s.mustBe(SortFP)
+	return uint(C.Z3_fpa_get_sbits(s.c.c, s.s))
+}
+
+// ArrayDomain returns the index sort of an array (or set) sort
+func (s *Sort) ArrayDomain() *Sort {
+	s.mustBe(SortArray)
+	return &Sort{c: s.c, s: C.Z3_get_array_sort_domain(s.c.c, s.s)}
+}
+
+// ArrayRange returns the value sort of an array sort (Bool for sets)
+func (s *Sort) ArrayRange() *Sort {
+	s.mustBe(SortArray)
+	return &Sort{c: s.c, s: C.Z3_get_array_sort_range(s.c.c, s.s)}
+}
+
+// mustBe panics when an accessor is used on the wrong family of sorts
+func (s *Sort) mustBe(kind SortKind) {
+	if s.Kind() != kind {
+		panic(fmt.Sprintf("z3: sort %s does not support this operation", s))
+	}
+}
@@ -0,0 +1,25 @@
+package z3
+
+/*
+#include <z3.h>
+*/
+import "C"
+
+// wrapStats converts Z3 statistics into a map from key to value
+// Counters and timings are both reported as float64.
+func (ctx *Context) wrapStats(st C.Z3_stats) map[string]float64 {
+	C.Z3_stats_inc_ref(ctx.c, st)
+	defer C.Z3_stats_dec_ref(ctx.c, st)
+
+	n := C.Z3_stats_size(ctx.c, st)
+	stats := make(map[string]float64, n)
+	for i := C.uint(0); i < n; i++ {
+		key := C.GoString(C.Z3_stats_get_key(ctx.c, st, i))
+		if bool(C.Z3_stats_is_uint(ctx.c, st, i)) {
+			stats[key] = float64(C.Z3_stats_get_uint_value(ctx.c, st, i))
+		} else {
+			stats[key] = float64(C.Z3_stats_get_double_value(ctx.c, st, i))
+		}
+	}
+	return stats
+}
@@ -0,0 +1,277 @@
+package z3
+
+// Strings in Z3 are sequences of characters. Most operations here also work
+// on general sequences created with SeqSort.
+
+/*
+#include <z3.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// StringSort returns the built-in String type
+func (ctx *Context) StringSort() *Sort {
+	return &Sort{c: ctx, s: C.Z3_mk_string_sort(ctx.c)}
+}
+
+// SeqSort returns the sort of sequences whose elements have sort elem
+func (ctx *Context) SeqSort(elem *Sort) *Sort {
+	return &Sort{c: ctx, s: C.Z3_mk_seq_sort(ctx.c, elem.s)}
+}
+
+// ReSort returns the sort of regular expressions over sequences of sort seq
+func (ctx *Context) ReSort(seq *Sort) *Sort {
+	return &Sort{c: ctx, s: C.Z3_mk_re_sort(ctx.c, seq.s)}
+}
+
+// maxChar is the largest character code Z3 accepts in strings
+const maxChar = 0x2FFFF
+
+// StringVal creates a string constant
+// Each Unicode code point of val becomes one character of the Z3 string.
+// StringVal panics on strings NewStringVal rejects; use NewStringVal for
+// input that is not known to be valid.
+func (ctx *Context) StringVal(val string) *Expr {
+	e, err := ctx.NewStringVal(val)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// NewStringVal creates a string constant, or returns an error if val is not
+// valid UTF-8 or holds a character beyond Z3's maximum of U+2FFFF
+func (ctx *Context) NewStringVal(val string) (*Expr, error) {
+	enc, err := encodeString(val)
+	if err != nil {
+		return nil, err
+	}
+	cVal := C.CString(enc)
+	defer C.free(unsafe.Pointer(cVal))
+	return ctx.wrap(C.Z3_mk_string(ctx.c, cVal)), nil
+}
+
+// encodeString escapes everything except printable ASCII as \u{hex} so that
+// Z3 reads one character per rune instead of one per UTF-8 byte
+func encodeString(val string) (string, error) {
+	if !utf8.ValidString(val) {
+		return "", fmt.Errorf("z3: string %q is not valid UTF-8", val)
+	}
+	var b strings.Builder
+	for _, r := range val {
+		if err := checkChar(r); err != nil {
+			return "", err
+		}
+		if r >= 0x20 && r < 0x7f && r != '\\' {
+			b.WriteRune(r)
+		} else {
+			fmt.Fprintf(&b, "\\u{%x}", r)
+		}
+	}
+	return b.String(), nil
+}
+
+// checkChar returns an error for characters Z3 strings cannot hold
+func checkChar(r rune) error {
+	if r > maxChar {
+		return fmt.Errorf("z3: character %U is beyond Z3's maximum %U", r, maxChar)
+	}
+	return nil
+}
+
+// decodeString reverses the \u{hex} escapes Z3 uses when printing strings
+func decodeString(val string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(val, "\\u{")
+		if i < 0 {
+			break
+		}
+		end := strings.IndexByte(val[i:], '}')
+		if end < 0 {
+			break
+		}
+		r, err := strconv.ParseUint(val[i+3:i+end], 16, 32)
+		if err != nil {
+			b.WriteString(val[:i+3])
+			val = val[i+3:]
+			continue
+		}
+		b.WriteString(val[:i])
+		b.WriteRune(rune(r))
+		val = val[i+end+1:]
+	}
+	b.WriteString(val)
+	return b.String()
+}
+
+// Concat joins strings or sequences: args[0] ++ args[1] ++ ...
+func (ctx *Context) Concat(args ...*Expr) *Expr {
+	cArgs := make([]C.Z3_ast, len(args))
+	for i, arg := range args {
+		cArgs[i] = arg.ast
+	}
+
+	var ptr *C.Z3_ast
+	if len(cArgs) > 0 {
+		ptr = &cArgs[0]
+	}
+
+	return ctx.wrap(C.Z3_mk_seq_concat(ctx.c, C.uint(len(args)), ptr))
+}
+
+// Length returns the length of a string or sequence as an Int
+func (ctx *Context) Length(s *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_seq_length(ctx.c, s.ast))
+}
+
+// Contains is true if sub occurs somewhere in s
+func (ctx *Context) Contains(s, sub *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_seq_contains(ctx.c, s.ast, sub.ast))
+}
+
+// PrefixOf is true if prefix is a prefix of s
+func (ctx *Context) PrefixOf(prefix, s *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_seq_prefix(ctx.c, prefix.ast, s.ast))
+}
+
+// SuffixOf is true if suffix is a suffix of s
+func (ctx *Context) SuffixOf(suffix, s *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_seq_suffix(ctx.c, suffix.ast, s.ast))
+}
+
+// IndexOf returns the first position of sub in s at or after offset, or -1
+func (ctx *Context) IndexOf(s, sub, offset *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_seq_index(ctx.c, s.ast, sub.ast, offset.ast))
+}
+
+// SubString extracts length characters of s starting at offset
+func (ctx *Context) SubString(s, offset, length *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_seq_extract(ctx.c, s.ast, offset.ast, length.ast))
+}
+
+// Replace substitutes the first occurrence of src in s by dst
+func (ctx *Context) Replace(s, src, dst *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_seq_replace(ctx.c, s.ast, src.ast, dst.ast))
+}
+
+// StrToInt converts a string of digits to a non-negative Int (-1 otherwise)
+func (ctx *Context) StrToInt(s *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_str_to_int(ctx.c, s.ast))
+}
+
+// IntToStr converts a non-negative Int to its decimal string ("" otherwise)
+func (ctx *Context) IntToStr(e *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_int_to_str(ctx.c, e.ast))
+}
+
+// Re creates the regular expression accepting exactly the sequence s
+func (ctx *Context) Re(s *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_seq_to_re(ctx.c, s.ast))
+}
+
+// ReStar is the Kleene star: re*
+func (ctx *Context) ReStar(re *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_re_star(ctx.c, re.ast))
+}
+
+// RePlus is one or more repetitions: re+
+func (ctx *Context) RePlus(re *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_re_plus(ctx.c, re.ast))
+}
+
+// ReOption is zero or one occurrence: re?
+func (ctx *Context) ReOption(re *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_re_option(ctx.c, re.ast))
+}
+
+// ReUnion accepts what any of args accepts: args[0] | args[1] | ...
+func (ctx *Context) ReUnion(args ...*Expr) *Expr {
+	cArgs := make([]C.Z3_ast, len(args))
+	for i, arg := range args {
+		cArgs[i] = arg.ast
+	}
+
+	var ptr *C.Z3_ast
+	if len(cArgs) > 0 {
+		ptr = &cArgs[0]
+	}
+
+	return ctx.wrap(C.Z3_mk_re_union(ctx.c, C.uint(len(args)), ptr))
+}
+
+// ReConcat accepts args[0] followed by args[1] followed by ...
+func (ctx *Context) ReConcat(args ...*Expr) *Expr {
+	cArgs := make([]C.Z3_ast, len(args))
+	for i, arg := range args {
+		cArgs[i] = arg.ast
+	}
+
+	var ptr *C.Z3_ast
+	if len(cArgs) > 0 {
+		ptr = &cArgs[0]
+	}
+
+	return ctx.wrap(C.Z3_mk_re_concat(ctx.c, C.uint(len(args)), ptr))
+}
+
+// ReRange accepts a single character between lo and hi (both one-character strings)
+func (ctx *Context) ReRange(lo, hi *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_re_range(ctx.c, lo.ast, hi.ast))
+}
+
+// ReIntersect accepts what all of args accept
+func (ctx *Context) ReIntersect(args ...*Expr) *Expr {
+	cArgs := make([]C.Z3_ast, len(args))
+	for i, arg := range args {
+		cArgs[i] = arg.ast
+	}
+
+	var ptr *C.Z3_ast
+	if len(cArgs) > 0 {
+		ptr = &cArgs[0]
+	}
+
+	return ctx.wrap(C.Z3_mk_re_intersect(ctx.c, C.uint(len(args)), ptr))
+}
+
+// ReComplement accepts exactly the sequences re rejects
+func (ctx *Context) ReComplement(re *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_re_complement(ctx.c, re.ast))
+}
+
+// ReLoop accepts between lo and hi repetitions of re (hi == 0 means no upper bound)
+func (ctx *Context) ReLoop(re *Expr, lo, hi uint) *Expr {
+	return ctx.wrap(C.Z3_mk_re_loop(ctx.c, re.ast, C.uint(lo), C.uint(hi)))
+}
+
+// ReEmpty is the regular expression of the given ReSort accepting nothing
+func (ctx *Context) ReEmpty(reSort *Sort) *Expr {
+	return ctx.wrap(C.Z3_mk_re_empty(ctx.c, reSort.s))
+}
+
+// ReFull is the regular expression of the given ReSort accepting everything
+func (ctx *Context) ReFull(reSort *Sort) *Expr {
+	return ctx.wrap(C.Z3_mk_re_full(ctx.c, reSort.s))
+}
+
+// InRe is true if the string s is accepted by the regular expression re
+func (ctx *Context) InRe(s, re *Expr) *Expr {
+	return ctx.wrap(C.Z3_mk_seq_in_re(ctx.c, s.ast, re.ast))
+}
+
+// EvalString evaluates a string expression in the model and returns its Go value
+func (m *Model) EvalString(e *Expr) (string, bool) {
+	res, ok := m.eval(e)
+	if !ok || !bool(C.Z3_is_string(m.ctx.c, res.ast)) {
+		return "", false
+	}
+	return decodeString(C.GoString(C.Z3_get_string(m.ctx.c, res.ast))), true
+}
@@ -0,0 +1,428 @@
+package z3
+
+// Go structs are mapped onto Z3 tuple (record) sorts. Each exported field
+// becomes a projection function, so `users.Age` turns into `Age(users)`.
+//
+// Field sorts are inferred from the Go type and can be overridden with a tag:
+//
+//	type Packet struct {
+//		Len   uint16 `z3:"bv16"`
+//		Valid bool
+//		Debug string `z3:"-"`
+//	}
+//
+// Supported tags: "bool", "int", "bvN" (N in 1..64), "float32", "float64", "-".
+
+/*
+#include <z3.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// StructSort is a Z3 record sort built from a Go struct type
+type StructSort struct {
+	*Sort
+	typ    reflect.Type
+	ctor   *FuncDecl
+	fields []structField
+}
+
+type structField struct {
+	index  int
+	name   string
+	kind   string // "bool", "int", "bv", "float32" or "float64"
+	bits   uint
+	acc    *FuncDecl
+	nested *StructSort
+}
+
+// StructSortOf builds (or returns the cached) record sort for the struct type of v.
+// v may be a struct value, a pointer to a struct or a reflect.Type.
+func (ctx *Context) StructSortOf(v interface{}) (*StructSort, error) {
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("z3: StructSortOf needs a struct type, got %v", t)
+	}
+	return ctx.structSort(t)
+}
+
+func (ctx *Context) structSort(t reflect.Type) (*StructSort, error) {
+	if ss, ok := ctx.structs[t]; ok {
+		return ss, nil
+	}
+
+	ss := &StructSort{typ: t}
+	var sorts []*Sort
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("z3")
+		if f.PkgPath != "" || tag == "-" {
+			continue // unexported or explicitly skipped
+		}
+
+		sf := structField{index: i, name: f.Name}
+		sort, err := ctx.fieldSort(&sf, f.Type, tag)
+		if err != nil {
+			return nil, fmt.Errorf("z3: field %s.%s: %v", t.Name(), f.Name, err)
+		}
+		ss.fields = append(ss.fields, sf)
+		sorts = append(sorts, sort)
+	}
+
+	cName := C.CString(ctx.structSortName(t))
+	defer C.free(unsafe.Pointer(cName))
+
+	cNames := make([]C.Z3_symbol, len(ss.fields))
+	cSorts := make([]C.Z3_sort, len(sorts))
+	for i, sf := range ss.fields {
+		cField := C.CString(sf.name)
+		cNames[i] = C.Z3_mk_string_symbol(ctx.c, cField)
+		C.free(unsafe.Pointer(cField))
+		cSorts[i] = sorts[i].s
+	}
+	cProj := make([]C.Z3_func_decl, len(ss.fields))
+
+	var namePtr *C.Z3_symbol
+	var sortPtr *C.Z3_sort
+	var projPtr *C.Z3_func_decl
+	if len(ss.fields) > 0 {
+		namePtr, sortPtr, projPtr = &cNames[0], &cSorts[0], &cProj[0]
+	}
+
+	var ctor C.Z3_func_decl
+	s := C.Z3_mk_tuple_sort(ctx.c, C.Z3_mk_string_symbol(ctx.c, cName),
+		C.uint(len(ss.fields)), namePtr, sortPtr, &ctor, projPtr)
+
+	ss.Sort = &Sort{c: ctx, s: s}
+	ss.ctor = ctx.wrapFuncDecl(ctor)
+	for i := range ss.fields {
+		ss.fields[i].acc = ctx.wrapFuncDecl(cProj[i])
+	}
+
+	if ctx.structs == nil {
+		ctx.structs = make(map[reflect.Type]*StructSort)
+	}
+	ctx.structs[t] = ss
+	return ss, nil
+}
+
+// structSortName gives each Go type its own Z3 sort name
+// Z3 replaces a tuple sort declared twice under one name, so types that print
+// alike (local types, anonymous structs) get a "!n" suffix.
+func (ctx *Context) structSortName(t reflect.Type) string {
+	name := structBaseName(t)
+	taken := 0
+	for other := range ctx.structs {
+		if structBaseName(other) == name {
+			taken++
+		}
+	}
+	if taken > 0 {
+		name += "!" + strconv.Itoa(taken)
+	}
+	return name
+}
+
+// structBaseName is the package-qualified type name, or the struct literal
+// type for anonymous structs
+func structBaseName(t reflect.Type) string {
+	if t.Name() != "" && t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	return t.String()
+}
+
+// fieldSort resolves the Z3 sort of a single struct field from its type and tag
+func (ctx *Context) fieldSort(sf *structField, t reflect.Type, tag string) (*Sort, error) {
+	switch {
+	case tag == "bool":
+		sf.kind = "bool"
+	case tag == "int":
+		sf.kind = "int"
+	case tag == "float32" || tag == "float64":
+		sf.kind = tag
+	case strings.HasPrefix(tag, "bv"):
+		bits, err := strconv.ParseUint(tag[2:], 10, 8)
+		if err != nil || bits == 0 || bits > 64 {
+			return nil, fmt.Errorf("invalid bit-vector tag %q", tag)
+		}
+		sf.kind, sf.bits = "bv", uint(bits)
+	case tag != "":
+		return nil, fmt.Errorf("unknown z3 tag %q", tag)
+	default:
+		switch t.Kind() {
+		case reflect.Bool:
+			sf.kind = "bool"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			sf.kind = "int"
+		case reflect.Float32:
+			sf.kind = "float32"
+		case reflect.Float64:
+			sf.kind = "float64"
+		case reflect.Struct:
+			nested, err := ctx.structSort(t)
+			if err != nil {
+				return nil, err
+			}
+			sf.nested = nested
+			return nested.Sort, nil
+		default:
+			return nil, fmt.Errorf("unsupported type %v", t)
+		}
+	}
+
+	if !goKindFits(sf.kind, t.Kind()) {
+		return nil, fmt.Errorf("tag %q does not fit Go type %v", sf.kind, t)
+	}
+
+	switch sf.kind {
+	case "bool":
+		return ctx.BoolSort(), nil
+	case "int":
+		return ctx.IntSort(), nil
+	case "bv":
+		return ctx.BVSort(sf.bits), nil
+	case "float32":
+		return ctx.Float32Sort(), nil
+	default:
+		return ctx.Float64Sort(), nil
+	}
+}
+
+func goKindFits(kind string, k reflect.Kind) bool {
+	switch kind {
+	case "bool":
+		return k == reflect.Bool
+	case "float32", "float64":
+		return k == reflect.Float32 || k == reflect.Float64
+	default:
+		return isSignedKind(k) || isUnsignedKind(k)
+	}
+}
+
+func isSignedKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUnsignedKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uint64
+}
+
+// Const creates a symbolic instance of the struct
+func (ss *StructSort) Const(name string) *Expr {
+	return ss.c.Const(name, ss.Sort)
+}
+
+// Field projects the named Go field out of a struct expression: e.Name
+func (ss *StructSort) Field(e *Expr, name string) *Expr {
+	for _, sf := range ss.fields {
+		if sf.name == name {
+			return ss.c.Apply(sf.acc, e)
+		}
+	}
+	panic(fmt.Sprintf("z3: %s has no field %q", ss.typ, name))
+}
+
+// Val builds a concrete struct expression holding the field values of v
+func (ss *StructSort) Val(v interface{}) (*Expr, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if !rv.IsValid() || rv.Type() != ss.typ {
+		return nil, fmt.Errorf("z3: Val expects %v, got %T", ss.typ, v)
+	}
+
+	ctx := ss.c
+	args := make([]*Expr, len(ss.fields))
+	for i, sf := range ss.fields {
+		fv := rv.Field(sf.index)
+		switch sf.kind {
+		case "bool":
+			if fv.Bool() {
+				args[i] = ctx.wrap(C.Z3_mk_true(ctx.c))
+			} else {
+				args[i] = ctx.wrap(C.Z3_mk_false(ctx.c))
+			}
+		case "int", "bv":
+			sort := ctx.IntSort()
+			if sf.kind == "bv" {
+				if !fitsBits(fv, sf.bits) {
+					return nil, fmt.Errorf("z3: field %s: %v does not fit in %d bits", sf.name, fv.Interface(), sf.bits)
+				}
+				sort = ctx.BVSort(sf.bits)
+			}
+			if isUnsignedKind(fv.Kind()) {
+				args[i] = ctx.wrap(C.Z3_mk_unsigned_int64(ctx.c, C.uint64_t(fv.Uint()), sort.s))
+			} else {
+				args[i] = ctx.wrap(C.Z3_mk_int64(ctx.c, C.int64_t(fv.Int()), sort.s))
+			}
+		case "float32":
+			args[i] = ctx.FloatVal(fv.Float(), ctx.Float32Sort())
+		case "float64":
+			args[i] = ctx.FloatVal(fv.Float(), ctx.Float64Sort())
+		default:
+			nested, err := sf.nested.Val(fv.Interface())
+			if err != nil {
+				return nil, err
+			}
+			args[i] = nested
+		}
+	}
+	return ctx.Apply(ss.ctor, args...), nil
+}
+
+// fitsBits reports whether an integer field survives a round trip through a
+// bit-vector of the given width: unsigned fields as natural numbers, signed
+// fields as two's complement, matching Decode
+func fitsBits(fv reflect.Value, bits uint) bool {
+	if bits >= 64 {
+		return true
+	}
+	if isUnsignedKind(fv.Kind()) {
+		return fv.Uint() < 1<<bits
+	}
+	v := fv.Int()
+	return v >= -(1<<(bits-1)) && v < 1<<(bits-1)
+}
+
+// Decode reads the value of the struct expression e from the model into out,
+// which must be a pointer to the Go struct type of ss.
+func (m *Model) Decode(ss *StructSort, e *Expr, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Type() != ss.typ {
+		return fmt.Errorf("z3: Decode expects *%v, got %T", ss.typ, out)
+	}
+	return m.decodeStruct(ss, e, rv.Elem())
+}
+
+func (m *Model) decodeStruct(ss *StructSort, e *Expr, rv reflect.Value) error {
+	ctx := m.ctx
+	for _, sf := range ss.fields {
+		fv := rv.Field(sf.index)
+		proj := ctx.Apply(sf.acc, e)
+
+		if sf.nested != nil {
+			if err := m.decodeStruct(sf.nested, proj, fv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		val, ok := m.eval(proj)
+		if !ok {
+			return fmt.Errorf("z3: cannot evaluate field %s", sf.name)
+		}
+
+		if sf.kind == "float32" || sf.kind == "float64" {
+			if special, ok := fpSpecial(val); ok {
+				fv.SetFloat(special)
+				continue
+			}
+			// Other floats are read back through their IEEE 754 bit
+			// pattern; Z3 leaves the pattern of NaN unspecified
+			if val, ok = m.eval(ctx.FPAToIEEEBV(val)); !ok {
+				return fmt.Errorf("z3: cannot evaluate field %s", sf.name)
+			}
+		}
+
+		if sf.kind == "bool" {
+			fv.SetBool(C.Z3_get_bool_value(ctx.c, val.ast) == C.Z3_L_TRUE)
+			continue
+		}
+
+		num := C.GoString(C.Z3_get_numeral_string(ctx.c, val.ast))
+		if err := setNumeral(fv, sf, num); err != nil {
+			return fmt.Errorf("z3: field %s: %v", sf.name, err)
+		}
+	}
+	return nil
+}
+
+// fpSpecial returns the Go value of a floating point numeral that is NaN,
+// an infinity or a zero
+func fpSpecial(val *Expr) (float64, bool) {
+	c := val.ctx.c
+	if C.Z3_fpa_is_numeral_nan(c, val.ast) {
+		return math.NaN(), true
+	}
+	sign := 1.0
+	if C.Z3_fpa_is_numeral_negative(c, val.ast) {
+		sign = -1
+	}
+	switch {
+	case bool(C.Z3_fpa_is_numeral_inf(c, val.ast)):
+		return math.Inf(int(sign)), true
+	case bool(C.Z3_fpa_is_numeral_zero(c, val.ast)):
+		return math.Copysign(0, sign), true
+	}
+	return 0, false
+}
+
+// setNumeral stores a decimal numeral produced by Z3 into a Go field
+func setNumeral(fv reflect.Value, sf structField, num string) error {
+	switch sf.kind {
+	case "float32", "float64":
+		bits, err := strconv.ParseUint(num, 10, 64)
+		if err != nil {
+			return err
+		}
+		if sf.kind == "float32" {
+			fv.SetFloat(float64(math.Float32frombits(uint32(bits))))
+		} else {
+			fv.SetFloat(math.Float64frombits(bits))
+		}
+		return nil
+
+	case "bv":
+		bits, err := strconv.ParseUint(num, 10, 64)
+		if err != nil {
+			return err
+		}
+		if isUnsignedKind(fv.Kind()) {
+			if fv.OverflowUint(bits) {
+				return fmt.Errorf("%s overflows %v", num, fv.Type())
+			}
+			fv.SetUint(bits)
+			return nil
+		}
+		// Signed fields read the bit-vector as two's complement
+		v := int64(bits)
+		if sf.bits < 64 && bits >= 1<<(sf.bits-1) {
+			v -= 1 << sf.bits
+		}
+		if fv.OverflowInt(v) {
+			return fmt.Errorf("%d overflows %v", v, fv.Type())
+		}
+		fv.SetInt(v)
+		return nil
+
+	default:
+		if isUnsignedKind(fv.Kind()) {
+			v, err := strconv.ParseUint(num, 10, 64)
+			if err != nil || fv.OverflowUint(v) {
+				return fmt.Errorf("%s overflows %v", num, fv.Type())
+			}
+			fv.SetUint(v)
+			return nil
+		}
+		v, err := strconv.ParseInt(num, 10, 64)
+		if err != nil || fv.OverflowInt(v) {
+			return fmt.Errorf("%s overflows %v", num, fv.Type())
+		}
+		fv.SetInt(v)
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package z3
+
+/*
+#include <z3.h>
+*/
+import "C"
+import "fmt"
+
+// BoundVar creates the de Bruijn variable #idx of the given sort
+// Bound variables are the placeholders that SubstituteVars and
+// SubstituteFuncs fill in; #0 is the innermost one.
+func (ctx *Context) BoundVar(idx uint, sort *Sort) *Expr {
+	return ctx.wrap(C.Z3_mk_bound(ctx.c, C.uint(idx), sort.s))
+}
+
+// Substitute replaces every occurrence of from[i] in e by to[i]
+// Example: x1 := body.Substitute([]*Expr{x}, []*Expr{xNext}) renames x to xNext
+func (e *Expr) Substitute(from, to []*Expr) *Expr {
+	if len(from) != len(to) {
+		panic(fmt.Sprintf("z3: Substitute got %d sources but %d targets", len(from), len(to)))
+	}
+	if len(from) == 0 {
+		return e
+	}
+
+	cFrom := make([]C.Z3_ast, len(from))
+	cTo := make([]C.Z3_ast, len(to))
+	for i := range from {
+		cFrom[i] = from[i].ast
+		cTo[i] = to[i].ast
+	}
+
+	return e.ctx.wrap(C.Z3_substitute(e.ctx.c, e.ast, C.uint(len(from)), &cFrom[0], &cTo[0]))
+}
+
+// SubstituteVars replaces each free bound variable #i in e by to[i]
+func (e *Expr) SubstituteVars(to []*Expr) *Expr {
+	cTo := make([]C.Z3_ast, len(to))
+	for i, t := range to {
+		cTo[i] = t.ast
+	}
+
+	var ptr *C.Z3_ast
+	if len(cTo) > 0 {
+		ptr = &cTo[0]
+	}
+
+	return e.ctx.wrap(C.Z3_substitute_vars(e.ctx.c, e.ast, C.uint(len(to)), ptr))
+}
+
+// SubstituteFuncs replaces every application fs[i](args...) in e by
+// bodies[i] with its bound variables #0, #1, ... set to args
+// Example: with body = Add(BoundVar(0, Int), one), f(f(x)) becomes (x + 1) + 1
+func (e *Expr) SubstituteFuncs(fs []*FuncDecl, bodies []*Expr) *Expr {
+	if len(fs) != len(bodies) {
+		panic(fmt.Sprintf("z3: SubstituteFuncs got %d functions but %d bodies", len(fs), len(bodies)))
+	}
+	if len(fs) == 0 {
+		return e
+	}
+
+	// Z3_substitute_funs only exists from Z3 4.12 on, so rebuild the term here
+	// bottom-up, visiting each shared subterm once
+	c := e.ctx.c
+	memo := make(map[C.uint]*Expr) // also keeps intermediate terms referenced
+	var visit func(a C.Z3_ast) C.Z3_ast
+	visit = func(a C.Z3_ast) C.Z3_ast {
+		id := C.Z3_get_ast_id(c, a)
+		if res, ok := memo[id]; ok {
+			return res.ast
+		}
+
+		res := a
+		switch C.Z3_get_ast_kind(c, a) {
+		case C.Z3_APP_AST:
+			app := C.Z3_to_app(c, a)
+			n := C.Z3_get_app_num_args(c, app)
+			args := make([]C.Z3_ast, n)
+			for i := C.uint(0); i < n; i++ {
+				args[i] = visit(C.Z3_get_app_arg(c, app, i))
+			}
+			var ptr *C.Z3_ast
+			if n > 0 {
+				ptr = &args[0]
+			}
+
+			decl := C.Z3_get_app_decl(c, app)
+			replaced := false
+			for i, f := range fs {
+				if bool(C.Z3_is_eq_func_decl(c, decl, f.d)) {
+					res = C.Z3_substitute_vars(c, bodies[i].ast, n, ptr)
+					replaced = true
+					break
+				}
+			}
+			if !replaced && n > 0 {
+				res = C.Z3_update_term(c, a, n, ptr)
+			}
+		case C.Z3_QUANTIFIER_AST:
+			body := visit(C.Z3_get_quantifier_body(c, a))
+			res = C.Z3_update_term(c, a, 1, &body)
+		}
+
+		memo[id] = e.ctx.wrap(res)
+		return res
+	}
+
+	visit(e.ast)
+	return memo[C.Z3_get_ast_id(c, e.ast)]
+}
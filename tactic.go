@@ -0,0 +1,343 @@
+package z3
+
+// Tactics transform goals (sets of formulas) into subgoals. They can be
+// combined into strategies and turned into solvers. Probes measure goals and
+// let strategies branch on them.
+
+/*
+#include <z3.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+type Tactic struct {
+	ctx *Context
+	t   C.Z3_tactic
+}
+
+type Goal struct {
+	ctx *Context
+	g   C.Z3_goal
+}
+
+type ApplyResult struct {
+	ctx *Context
+	r   C.Z3_apply_result
+}
+
+type Probe struct {
+	ctx *Context
+	p   C.Z3_probe
+}
+
+// NewTactic looks up a built-in tactic such as "simplify", "solve-eqs" or "bit-blast"
+func (ctx *Context) NewTactic(name string) (*Tactic, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	defer ctx.recoverable()()
+	t := C.Z3_mk_tactic(ctx.c, cName)
+	if err := ctx.lastError(); err != nil {
+		return nil, err
+	}
+	return ctx.wrapTactic(t), nil
+}
+
+// TacticNames lists the built-in tactics accepted by NewTactic
+func (ctx *Context) TacticNames() []string {
+	n := C.Z3_get_num_tactics(ctx.c)
+	names := make([]string, n)
+	for i := C.uint(0); i < n; i++ {
+		names[i] = C.GoString(C.Z3_get_tactic_name(ctx.c, i))
+	}
+	return names
+}
+
+// TacticDescription returns the one-line description of a built-in tactic
+func (ctx *Context) TacticDescription(name string) (string, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	defer ctx.recoverable()()
+	descr := C.Z3_tactic_get_descr(ctx.c, cName)
+	if err := ctx.lastError(); err != nil {
+		return "", err
+	}
+	return C.GoString(descr), nil
+}
+
+func (ctx *Context) wrapTactic(t C.Z3_tactic) *Tactic {
+	tac := &Tactic{ctx: ctx, t: t}
+	C.Z3_tactic_inc_ref(ctx.c, t)
+
+	runtime.SetFinalizer(tac, func(tac *Tactic) {
+		C.Z3_tactic_dec_ref(tac.ctx.c, tac.t)
+	})
+	return tac
+}
+
+// AndThen runs t and then each of next on every resulting subgoal
+func (t *Tactic) AndThen(next ...*Tactic) *Tactic {
+	res := t
+	for _, n := range next {
+		res = t.ctx.wrapTactic(C.Z3_tactic_and_then(t.ctx.c, res.t, n.t))
+	}
+	return res
+}
+
+// OrElse runs t, and other if t fails
+func (t *Tactic) OrElse(other *Tactic) *Tactic {
+	return t.ctx.wrapTactic(C.Z3_tactic_or_else(t.ctx.c, t.t, other.t))
+}
+
+// TryFor fails if t does not finish within ms milliseconds
+func (t *Tactic) TryFor(ms uint) *Tactic {
+	return t.ctx.wrapTactic(C.Z3_tactic_try_for(t.ctx.c, t.t, C.uint(ms)))
+}
+
+// Repeat applies t to its own subgoals until a fixpoint or max rounds
+func (t *Tactic) Repeat(max uint) *Tactic {
+	return t.ctx.wrapTactic(C.Z3_tactic_repeat(t.ctx.c, t.t, C.uint(max)))
+}
+
+// ParOr runs the tactics in parallel and keeps the first one that succeeds
+func (ctx *Context) ParOr(tactics ...*Tactic) *Tactic {
+	cTactics := make([]C.Z3_tactic, len(tactics))
+	for i, t := range tactics {
+		cTactics[i] = t.t
+	}
+
+	var ptr *C.Z3_tactic
+	if len(cTactics) > 0 {
+		ptr = &cTactics[0]
+	}
+
+	return ctx.wrapTactic(C.Z3_tactic_par_or(ctx.c, C.uint(len(tactics)), ptr))
+}
+
+// Cond runs then if the probe is true on the goal, and otherwise else
+func (ctx *Context) Cond(p *Probe, then, otherwise *Tactic) *Tactic {
+	return ctx.wrapTactic(C.Z3_tactic_cond(ctx.c, p.p, then.t, otherwise.t))
+}
+
+// Apply runs the tactic on a goal
+func (t *Tactic) Apply(g *Goal) (*ApplyResult, error) {
+	defer t.ctx.recoverable()()
+	r := C.Z3_tactic_apply(t.ctx.c, t.t, g.g)
+	if err := t.ctx.lastError(); err != nil {
+		return nil, err
+	}
+
+	res := &ApplyResult{ctx: t.ctx, r: r}
+	C.Z3_apply_result_inc_ref(t.ctx.c, r)
+
+	runtime.SetFinalizer(res, func(res *ApplyResult) {
+		C.Z3_apply_result_dec_ref(res.ctx.c, res.r)
+	})
+	return res, nil
+}
+
+// Solver creates a solver that runs the tactic on its assertions
+func (t *Tactic) Solver() *Solver {
+	return t.ctx.NewSolverFromTactic(t)
+}
+
+// Help describes the parameters the tactic accepts
+func (t *Tactic) Help() string {
+	return C.GoString(C.Z3_tactic_get_help(t.ctx.c, t.t))
+}
+
+// NewGoal creates an empty goal; the flags enable model, unsat core and
+// proof tracking through tactics
+func (ctx *Context) NewGoal(models, unsatCores, proofs bool) *Goal {
+	return ctx.wrapGoal(C.Z3_mk_goal(ctx.c, C.bool(models), C.bool(unsatCores), C.bool(proofs)))
+}
+
+func (ctx *Context) wrapGoal(g C.Z3_goal) *Goal {
+	goal := &Goal{ctx: ctx, g: g}
+	C.Z3_goal_inc_ref(ctx.c, g)
+
+	runtime.SetFinalizer(goal, func(goal *Goal) {
+		C.Z3_goal_dec_ref(goal.ctx.c, goal.g)
+	})
+	return goal
+}
+
+// Assert adds formulas to the goal
+func (g *Goal) Assert(formulas ...*Expr) {
+	for _, f := range formulas {
+		C.Z3_goal_assert(g.ctx.c, g.g, f.ast)
+	}
+}
+
+// Size returns the number of formulas in the goal
+func (g *Goal) Size() int {
+	return int(C.Z3_goal_size(g.ctx.c, g.g))
+}
+
+// Formulas returns the formulas in the goal
+func (g *Goal) Formulas() []*Expr {
+	n := C.Z3_goal_size(g.ctx.c, g.g)
+	formulas := make([]*Expr, n)
+	for i := C.uint(0); i < n; i++ {
+		formulas[i] = g.ctx.wrap(C.Z3_goal_formula(g.ctx.c, g.g, i))
+	}
+	return formulas
+}
+
+// AsExpr returns the conjunction of the goal's formulas (true if empty)
+func (g *Goal) AsExpr() *Expr {
+	formulas := g.Formulas()
+	if len(formulas) == 0 {
+		return g.ctx.wrap(C.Z3_mk_true(g.ctx.c))
+	}
+	if len(formulas) == 1 {
+		return formulas[0]
+	}
+	return g.ctx.And(formulas...)
+}
+
+// IsDecidedSat is true if the goal is empty, i.e. trivially satisfiable
+func (g *Goal) IsDecidedSat() bool {
+	return bool(C.Z3_goal_is_decided_sat(g.ctx.c, g.g))
+}
+
+// IsDecidedUnsat is true if the goal contains false
+func (g *Goal) IsDecidedUnsat() bool {
+	return bool(C.Z3_goal_is_decided_unsat(g.ctx.c, g.g))
+}
+
+func (g *Goal) String() string {
+	return C.GoString(C.Z3_goal_to_string(g.ctx.c, g.g))
+}
+
+// NumSubgoals returns how many subgoals the tactic produced
+func (r *ApplyResult) NumSubgoals() int {
+	return int(C.Z3_apply_result_get_num_subgoals(r.ctx.c, r.r))
+}
+
+// Subgoal returns the i-th subgoal
+func (r *ApplyResult) Subgoal(i int) *Goal {
+	if i < 0 || i >= r.NumSubgoals() {
+		panic(fmt.Sprintf("z3: subgoal %d out of range for %d subgoals", i, r.NumSubgoals()))
+	}
+	return r.ctx.wrapGoal(C.Z3_apply_result_get_subgoal(r.ctx.c, r.r, C.uint(i)))
+}
+
+// Subgoals returns all subgoals; the original goal is equivalent to their disjunction
+func (r *ApplyResult) Subgoals() []*Goal {
+	goals := make([]*Goal, r.NumSubgoals())
+	for i := range goals {
+		goals[i] = r.Subgoal(i)
+	}
+	return goals
+}
+
+// AsExpr returns the disjunction of the subgoals (false if there are none)
+func (r *ApplyResult) AsExpr() *Expr {
+	goals := r.Subgoals()
+	switch len(goals) {
+	case 0:
+		return r.ctx.wrap(C.Z3_mk_false(r.ctx.c))
+	case 1:
+		return goals[0].AsExpr()
+	}
+
+	disjuncts := make([]*Expr, len(goals))
+	for i, g := range goals {
+		disjuncts[i] = g.AsExpr()
+	}
+	return r.ctx.Or(disjuncts...)
+}
+
+func (r *ApplyResult) String() string {
+	return C.GoString(C.Z3_apply_result_to_string(r.ctx.c, r.r))
+}
+
+// NewProbe looks up a built-in probe such as "num-consts" or "is-qfbv"
+func (ctx *Context) NewProbe(name string) (*Probe, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	defer ctx.recoverable()()
+	p := C.Z3_mk_probe(ctx.c, cName)
+	if err := ctx.lastError(); err != nil {
+		return nil, err
+	}
+	return ctx.wrapProbe(p), nil
+}
+
+// ProbeNames lists the built-in probes accepted by NewProbe
+func (ctx *Context) ProbeNames() []string {
+	n := C.Z3_get_num_probes(ctx.c)
+	names := make([]string, n)
+	for i := C.uint(0); i < n; i++ {
+		names[i] = C.GoString(C.Z3_get_probe_name(ctx.c, i))
+	}
+	return names
+}
+
+// ProbeConst is a probe that always returns val
+func (ctx *Context) ProbeConst(val float64) *Probe {
+	return ctx.wrapProbe(C.Z3_probe_const(ctx.c, C.double(val)))
+}
+
+func (ctx *Context) wrapProbe(p C.Z3_probe) *Probe {
+	probe := &Probe{ctx: ctx, p: p}
+	C.Z3_probe_inc_ref(ctx.c, p)
+
+	runtime.SetFinalizer(probe, func(probe *Probe) {
+		C.Z3_probe_dec_ref(probe.ctx.c, probe.p)
+	})
+	return probe
+}
+
+// Apply measures the goal; boolean probes return 1 for true and 0 for false
+func (p *Probe) Apply(g *Goal) float64 {
+	return float64(C.Z3_probe_apply(p.ctx.c, p.p, g.g))
+}
+
+// Lt is the probe p < other
+func (p *Probe) Lt(other *Probe) *Probe {
+	return p.ctx.wrapProbe(C.Z3_probe_lt(p.ctx.c, p.p, other.p))
+}
+
+// Gt is the probe p > other
+func (p *Probe) Gt(other *Probe) *Probe {
+	return p.ctx.wrapProbe(C.Z3_probe_gt(p.ctx.c, p.p, other.p))
+}
+
+// Le is the probe p <= other
+func (p *Probe) Le(other *Probe) *Probe {
+	return p.ctx.wrapProbe(C.Z3_probe_le(p.ctx.c, p.p, other.p))
+}
+
+// Ge is the probe p >= other
+func (p *Probe) Ge(other *Probe) *Probe {
+	return p.ctx.wrapProbe(C.Z3_probe_ge(p.ctx.c, p.p, other.p))
+}
+
+// Eq is the probe p == other
+func (p *Probe) Eq(other *Probe) *Probe {
+	return p.ctx.wrapProbe(C.Z3_probe_eq(p.ctx.c, p.p, other.p))
+}
+
+// And is the probe p && other
+func (p *Probe) And(other *Probe) *Probe {
+	return p.ctx.wrapProbe(C.Z3_probe_and(p.ctx.c, p.p, other.p))
+}
+
+// Or is the probe p || other
+func (p *Probe) Or(other *Probe) *Probe {
+	return p.ctx.wrapProbe(C.Z3_probe_or(p.ctx.c, p.p, other.p))
+}
+
+// Not is the probe !p
+func (p *Probe) Not() *Probe {
+	return p.ctx.wrapProbe(C.Z3_probe_not(p.ctx.c, p.p))
+}
@@ -0,0 +1,441 @@
+package z3
+
+// The typed layer wraps *Expr in one Go type per sort family so that mixing
+// kinds, such as adding a Bool to a BV, is a compile error instead of a
+// fatal Z3 error. Every typed value converts back with Expr(), and As
+// converts an untyped *Expr after checking its sort.
+
+/*
+#include <z3.h>
+#include <stdint.h>
+*/
+import "C"
+import "fmt"
+
+// Value is implemented by the typed wrappers: Bool, Int, Real, BV, Float and Array
+type Value[T any] interface {
+	Expr() *Expr
+	// accepts reports whether expressions of sort s can be wrapped as T
+	accepts(s *Sort) bool
+	// wrap converts e without checking its sort
+	wrap(e *Expr) T
+}
+
+// As converts e to the typed wrapper T, e.g. As[Int](e)
+func As[T Value[T]](e *Expr) (T, error) {
+	var zero T
+	if !zero.accepts(e.Sort()) {
+		return zero, fmt.Errorf("z3: %s has sort %s, not %T", e, e.Sort(), zero)
+	}
+	return zero.wrap(e), nil
+}
+
+// If is the typed if-then-else term: if c then t else e
+func If[T Value[T]](c Bool, t, e T) T {
+	t.Expr().checkOperand("If", e.Expr())
+	return t.wrap(c.e.ctx.ITE(c.e, t.Expr(), e.Expr()))
+}
+
+type Bool struct{ e *Expr }
+
+// BoolConst creates a Boolean variable
+func (ctx *Context) BoolConst(name string) Bool {
+	return Bool{ctx.Const(name, ctx.BoolSort())}
+}
+
+// BoolLit creates the constant true or false
+func (ctx *Context) BoolLit(v bool) Bool {
+	if v {
+		return Bool{ctx.wrap(C.Z3_mk_true(ctx.c))}
+	}
+	return Bool{ctx.wrap(C.Z3_mk_false(ctx.c))}
+}
+
+func (b Bool) Expr() *Expr             { return b.e }
+func (b Bool) String() string          { return b.e.String() }
+func (Bool) accepts(s *Sort) bool      { return s.Kind() == SortBool }
+func (Bool) wrap(e *Expr) Bool         { return Bool{e} }
+func (b Bool) Eq(other Bool) Bool      { return Bool{b.e.ctx.Eq(b.e, other.e)} }
+func (b Bool) Not() Bool               { return Bool{b.e.ctx.Not(b.e)} }
+func (b Bool) Implies(other Bool) Bool { return Bool{b.e.ctx.Implies(b.e, other.e)} }
+func (b Bool) Xor(other Bool) Bool     { return Bool{b.e.ctx.Xor(b.e, other.e)} }
+func (b Bool) And(others ...Bool) Bool { return Bool{b.e.ctx.And(boolArgs(b, others)...)} }
+func (b Bool) Or(others ...Bool) Bool  { return Bool{b.e.ctx.Or(boolArgs(b, others)...)} }
+
+func boolArgs(first Bool, rest []Bool) []*Expr {
+	args := make([]*Expr, 0, len(rest)+1)
+	args = append(args, first.e)
+	for _, r := range rest {
+		args = append(args, r.e)
+	}
+	return args
+}
+
+type Int struct{ e *Expr }
+
+// IntConst creates an integer variable
+func (ctx *Context) IntConst(name string) Int {
+	return Int{ctx.Const(name, ctx.IntSort())}
+}
+
+// IntLit creates an integer numeral
+func (ctx *Context) IntLit(v int64) Int {
+	return Int{ctx.wrap(C.Z3_mk_int64(ctx.c, C.int64_t(v), C.Z3_mk_int_sort(ctx.c)))}
+}
+
+func (x Int) Expr() *Expr        { return x.e }
+func (x Int) String() string     { return x.e.String() }
+func (Int) accepts(s *Sort) bool { return s.Kind() == SortInt }
+func (Int) wrap(e *Expr) Int     { return Int{e} }
+func (x Int) Add(y Int) Int      { return Int{x.e.ctx.Add(x.e, y.e)} }
+func (x Int) Mul(y Int) Int      { return Int{x.e.ctx.Mul(x.e, y.e)} }
+func (x Int) Mod(y Int) Int      { return Int{x.e.ctx.Mod(x.e, y.e)} }
+func (x Int) Sub(y Int) Int      { return Int{sub(x.e, y.e)} }
+func (x Int) Neg() Int           { return Int{x.e.ctx.wrap(C.Z3_mk_unary_minus(x.e.ctx.c, x.e.ast))} }
+
+// Div is integer division rounding towards negative infinity for positive divisors
+func (x Int) Div(y Int) Int { return Int{x.e.ctx.wrap(C.Z3_mk_div(x.e.ctx.c, x.e.ast, y.e.ast))} }
+func (x Int) Eq(y Int) Bool { return Bool{x.e.ctx.Eq(x.e, y.e)} }
+func (x Int) Lt(y Int) Bool { return Bool{x.e.ctx.LT(x.e, y.e)} }
+func (x Int) Gt(y Int) Bool { return Bool{x.e.ctx.GT(x.e, y.e)} }
+func (x Int) Le(y Int) Bool { return Bool{x.e.ctx.wrap(C.Z3_mk_le(x.e.ctx.c, x.e.ast, y.e.ast))} }
+func (x Int) Ge(y Int) Bool { return Bool{x.e.ctx.wrap(C.Z3_mk_ge(x.e.ctx.c, x.e.ast, y.e.ast))} }
+
+// ToReal converts x to a Real with the same value
+func (x Int) ToReal() Real { return Real{x.e.ctx.wrap(C.Z3_mk_int2real(x.e.ctx.c, x.e.ast))} }
+
+type Real struct{ e *Expr }
+
+// RealConst creates a real variable
+func (ctx *Context) RealConst(name string) Real {
+	return Real{ctx.Const(name, ctx.RealSort())}
+}
+
+// RealLit creates the rational numeral num/den
+func (ctx *Context) RealLit(num, den int) Real {
+	return Real{ctx.wrap(C.Z3_mk_real(ctx.c, C.int(num), C.int(den)))}
+}
+
+func (x Real) Expr() *Expr        { return x.e }
+func (x Real) String() string     { return x.e.String() }
+func (Real) accepts(s *Sort) bool { return s.Kind() == SortReal }
+func (Real) wrap(e *Expr) Real    { return Real{e} }
+func (x Real) Add(y Real) Real    { return Real{x.e.ctx.Add(x.e, y.e)} }
+func (x Real) Mul(y Real) Real    { return Real{x.e.ctx.Mul(x.e, y.e)} }
+func (x Real) Sub(y Real) Real    { return Real{sub(x.e, y.e)} }
+func (x Real) Div(y Real) Real    { return Real{x.e.ctx.wrap(C.Z3_mk_div(x.e.ctx.c, x.e.ast, y.e.ast))} }
+func (x Real) Neg() Real          { return Real{x.e.ctx.wrap(C.Z3_mk_unary_minus(x.e.ctx.c, x.e.ast))} }
+func (x Real) Eq(y Real) Bool     { return Bool{x.e.ctx.Eq(x.e, y.e)} }
+func (x Real) Lt(y Real) Bool     { return Bool{x.e.ctx.LT(x.e, y.e)} }
+func (x Real) Gt(y Real) Bool     { return Bool{x.e.ctx.GT(x.e, y.e)} }
+func (x Real) Le(y Real) Bool     { return Bool{x.e.ctx.wrap(C.Z3_mk_le(x.e.ctx.c, x.e.ast, y.e.ast))} }
+func (x Real) Ge(y Real) Bool     { return Bool{x.e.ctx.wrap(C.Z3_mk_ge(x.e.ctx.c, x.e.ast, y.e.ast))} }
+
+// ToInt rounds x down to the nearest integer
+func (x Real) ToInt() Int { return Int{x.e.ctx.wrap(C.Z3_mk_real2int(x.e.ctx.c, x.e.ast))} }
+
+// sub builds l - r for Int or Real operands
+func sub(l, r *Expr) *Expr {
+	args := [2]C.Z3_ast{l.ast, r.ast}
+	return l.ctx.wrap(C.Z3_mk_sub(l.ctx.c, 2, &args[0]))
+}
+
+// BV is a bit-vector of any width; operands of binary methods must have the
+// same width, which is checked when the term is built
+type BV struct{ e *Expr }
+
+// BVConst creates a bit-vector variable of the given width
+func (ctx *Context) BVConst(name string, bits uint) BV {
+	return BV{ctx.Const(name, ctx.BVSort(bits))}
+}
+
+// BVLit creates a bit-vector numeral; v is truncated to bits
+func (ctx *Context) BVLit(v int64, bits uint) BV {
+	return BV{ctx.BVVal(v, bits)}
+}
+
+func (x BV) Expr() *Expr        { return x.e }
+func (x BV) String() string     { return x.e.String() }
+func (BV) accepts(s *Sort) bool { return s.Kind() == SortBV }
+func (BV) wrap(e *Expr) BV      { return BV{e} }
+
+// Width returns the number of bits of x
+func (x BV) Width() uint { return x.e.Sort().BVSize() }
+
+func (x BV) Add(y BV) BV {
+	x.checkWidth(y)
+	return BV{x.e.ctx.wrap(C.Z3_mk_bvadd(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) Sub(y BV) BV {
+	x.checkWidth(y)
+	return BV{x.e.ctx.wrap(C.Z3_mk_bvsub(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) Mul(y BV) BV {
+	x.checkWidth(y)
+	return BV{x.e.ctx.wrap(C.Z3_mk_bvmul(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) UDiv(y BV) BV {
+	x.checkWidth(y)
+	return BV{x.e.ctx.wrap(C.Z3_mk_bvudiv(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) SDiv(y BV) BV {
+	x.checkWidth(y)
+	return BV{x.e.ctx.wrap(C.Z3_mk_bvsdiv(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) URem(y BV) BV {
+	x.checkWidth(y)
+	return BV{x.e.ctx.wrap(C.Z3_mk_bvurem(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) SRem(y BV) BV {
+	x.checkWidth(y)
+	return BV{x.e.ctx.wrap(C.Z3_mk_bvsrem(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) And(y BV) BV {
+	x.checkWidth(y)
+	return BV{x.e.ctx.wrap(C.Z3_mk_bvand(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) Or(y BV) BV {
+	x.checkWidth(y)
+	return BV{x.e.ctx.wrap(C.Z3_mk_bvor(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) Xor(y BV) BV {
+	x.checkWidth(y)
+	return BV{x.e.ctx.wrap(C.Z3_mk_bvxor(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) Shl(y BV) BV {
+	x.checkWidth(y)
+	return BV{x.e.ctx.wrap(C.Z3_mk_bvshl(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) LShr(y BV) BV {
+	x.checkWidth(y)
+	return BV{x.e.ctx.wrap(C.Z3_mk_bvlshr(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) AShr(y BV) BV {
+	x.checkWidth(y)
+	return BV{x.e.ctx.wrap(C.Z3_mk_bvashr(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) Not() BV { return BV{x.e.ctx.wrap(C.Z3_mk_bvnot(x.e.ctx.c, x.e.ast))} }
+func (x BV) Neg() BV { return BV{x.e.ctx.wrap(C.Z3_mk_bvneg(x.e.ctx.c, x.e.ast))} }
+
+func (x BV) Eq(y BV) Bool {
+	x.checkWidth(y)
+	return Bool{x.e.ctx.wrap(C.Z3_mk_eq(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) ULt(y BV) Bool {
+	x.checkWidth(y)
+	return Bool{x.e.ctx.wrap(C.Z3_mk_bvult(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) ULe(y BV) Bool {
+	x.checkWidth(y)
+	return Bool{x.e.ctx.wrap(C.Z3_mk_bvule(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) UGt(y BV) Bool {
+	x.checkWidth(y)
+	return Bool{x.e.ctx.wrap(C.Z3_mk_bvugt(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) UGe(y BV) Bool {
+	x.checkWidth(y)
+	return Bool{x.e.ctx.wrap(C.Z3_mk_bvuge(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) SLt(y BV) Bool {
+	x.checkWidth(y)
+	return Bool{x.e.ctx.wrap(C.Z3_mk_bvslt(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) SLe(y BV) Bool {
+	x.checkWidth(y)
+	return Bool{x.e.ctx.wrap(C.Z3_mk_bvsle(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) SGt(y BV) Bool {
+	x.checkWidth(y)
+	return Bool{x.e.ctx.wrap(C.Z3_mk_bvsgt(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x BV) SGe(y BV) Bool {
+	x.checkWidth(y)
+	return Bool{x.e.ctx.wrap(C.Z3_mk_bvsge(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+// Concat joins x (high bits) and y (low bits)
+func (x BV) Concat(y BV) BV {
+	return BV{x.e.ctx.wrap(C.Z3_mk_concat(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+// Extract returns bits hi down to lo of x; it panics unless
+// lo <= hi < x.Width()
+func (x BV) Extract(hi, lo uint) BV {
+	if lo > hi || hi >= x.Width() {
+		panic(fmt.Sprintf("z3: Extract(%d, %d) out of range for %d bits", hi, lo, x.Width()))
+	}
+	return BV{x.e.ctx.wrap(C.Z3_mk_extract(x.e.ctx.c, C.uint(hi), C.uint(lo), x.e.ast))}
+}
+
+// ToInt converts x to an Int, reading it as two's complement if signed
+func (x BV) ToInt(signed bool) Int {
+	return Int{x.e.ctx.wrap(C.Z3_mk_bv2int(x.e.ctx.c, x.e.ast, C.bool(signed)))}
+}
+
+// checkWidth panics if x and y differ in width
+func (x BV) checkWidth(y BV) {
+	if xw, yw := x.Width(), y.Width(); xw != yw {
+		panic(fmt.Sprintf("z3: bit-vector width mismatch: %d and %d", xw, yw))
+	}
+}
+
+// Float is an IEEE 754 floating point value; arithmetic rounds to nearest,
+// ties to even. Operands of binary methods must have the same format, which
+// is checked when the term is built.
+type Float struct{ e *Expr }
+
+// FloatConst creates a floating point variable, e.g. of Float64Sort
+func (ctx *Context) FloatConst(name string, sort *Sort) Float {
+	mustBeFloatSort(sort)
+	return Float{ctx.Const(name, sort)}
+}
+
+// FloatLit creates a floating point numeral
+func (ctx *Context) FloatLit(v float64, sort *Sort) Float {
+	mustBeFloatSort(sort)
+	return Float{ctx.FloatVal(v, sort)}
+}
+
+func mustBeFloatSort(sort *Sort) {
+	if sort.Kind() != SortFP {
+		panic(fmt.Sprintf("z3: %s is not a floating point sort", sort))
+	}
+}
+
+func (x Float) Expr() *Expr        { return x.e }
+func (x Float) String() string     { return x.e.String() }
+func (Float) accepts(s *Sort) bool { return s.Kind() == SortFP }
+func (Float) wrap(e *Expr) Float   { return Float{e} }
+
+func (x Float) Add(y Float) Float {
+	x.checkFormat(y)
+	ctx := x.e.ctx
+	return Float{ctx.wrap(C.Z3_mk_fpa_add(ctx.c, ctx.RNE().ast, x.e.ast, y.e.ast))}
+}
+
+func (x Float) Sub(y Float) Float {
+	x.checkFormat(y)
+	ctx := x.e.ctx
+	return Float{ctx.wrap(C.Z3_mk_fpa_sub(ctx.c, ctx.RNE().ast, x.e.ast, y.e.ast))}
+}
+
+func (x Float) Mul(y Float) Float {
+	x.checkFormat(y)
+	ctx := x.e.ctx
+	return Float{ctx.wrap(C.Z3_mk_fpa_mul(ctx.c, ctx.RNE().ast, x.e.ast, y.e.ast))}
+}
+
+func (x Float) Div(y Float) Float {
+	x.checkFormat(y)
+	ctx := x.e.ctx
+	return Float{ctx.wrap(C.Z3_mk_fpa_div(ctx.c, ctx.RNE().ast, x.e.ast, y.e.ast))}
+}
+
+func (x Float) Neg() Float  { return Float{x.e.ctx.FPANeg(x.e)} }
+func (x Float) IsNaN() Bool { return Bool{x.e.ctx.FPAIsNaN(x.e)} }
+
+// Eq is IEEE equality: NaN differs from itself and -0 equals +0
+func (x Float) Eq(y Float) Bool {
+	x.checkFormat(y)
+	return Bool{x.e.ctx.FPAEq(x.e, y.e)}
+}
+
+func (x Float) Lt(y Float) Bool {
+	x.checkFormat(y)
+	return Bool{x.e.ctx.FPALt(x.e, y.e)}
+}
+
+func (x Float) Gt(y Float) Bool {
+	x.checkFormat(y)
+	return Bool{x.e.ctx.FPAGt(x.e, y.e)}
+}
+
+func (x Float) Le(y Float) Bool {
+	x.checkFormat(y)
+	return Bool{x.e.ctx.wrap(C.Z3_mk_fpa_leq(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+func (x Float) Ge(y Float) Bool {
+	x.checkFormat(y)
+	return Bool{x.e.ctx.wrap(C.Z3_mk_fpa_geq(x.e.ctx.c, x.e.ast, y.e.ast))}
+}
+
+// checkFormat panics if x and y differ in exponent or significand bits
+func (x Float) checkFormat(y Float) {
+	xs, ys := x.e.Sort(), y.e.Sort()
+	if xs.FPEbits() != ys.FPEbits() || xs.FPSbits() != ys.FPSbits() {
+		panic(fmt.Sprintf("z3: floating point format mismatch: %s and %s", xs, ys))
+	}
+}
+
+// Array maps indices of type K to values of type V
+type Array[K Value[K], V Value[V]] struct{ e *Expr }
+
+// ArrayConst creates an array variable; the sorts must fit K and V
+func ArrayConst[K Value[K], V Value[V]](ctx *Context, name string, domain, rangeSort *Sort) Array[K, V] {
+	return mustArray[K, V](ctx.Const(name, ctx.ArraySort(domain, rangeSort)))
+}
+
+// ConstArrayOf creates the array that maps every index of domain to v
+func ConstArrayOf[K Value[K], V Value[V]](ctx *Context, domain *Sort, v V) Array[K, V] {
+	return mustArray[K, V](ctx.ConstArray(domain, v.Expr()))
+}
+
+func mustArray[K Value[K], V Value[V]](e *Expr) Array[K, V] {
+	a, err := As[Array[K, V]](e)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func (a Array[K, V]) Expr() *Expr    { return a.e }
+func (a Array[K, V]) String() string { return a.e.String() }
+
+func (Array[K, V]) accepts(s *Sort) bool {
+	var k K
+	var v V
+	return s.Kind() == SortArray && k.accepts(s.ArrayDomain()) && v.accepts(s.ArrayRange())
+}
+
+func (Array[K, V]) wrap(e *Expr) Array[K, V] { return Array[K, V]{e} }
+
+// Select reads a[i]
+func (a Array[K, V]) Select(i K) V {
+	a.e.checkArray("Select", i.Expr(), nil)
+	var v V
+	return v.wrap(a.e.ctx.Select(a.e, i.Expr()))
+}
+
+// Store returns a copy of a with a[i] = v
+func (a Array[K, V]) Store(i K, v V) Array[K, V] {
+	a.e.checkArray("Store", i.Expr(), v.Expr())
+	return Array[K, V]{a.e.ctx.Store(a.e, i.Expr(), v.Expr())}
+}
+
+func (a Array[K, V]) Eq(b Array[K, V]) Bool {
+	a.e.checkOperand("Eq", b.e)
+	return Bool{a.e.ctx.Eq(a.e, b.e)}
+}
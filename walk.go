@@ -0,0 +1,100 @@
+package z3
+
+/*
+#include <z3.h>
+*/
+import "C"
+
+// Walk visits e and its subterms in pre-order, each shared subterm once
+// If fn returns false the children of that node are skipped. Quantifier
+// bodies are visited as the single child of the quantifier.
+func Walk(e *Expr, fn func(*Expr) bool) {
+	ctx := e.ctx
+	seen := make(map[C.uint]bool)
+
+	// An explicit stack avoids deep Go recursion on long chains
+	stack := []*Expr{e}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		id := C.Z3_get_ast_id(ctx.c, n.ast)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if !fn(n) {
+			continue
+		}
+		children := n.children()
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, children[i])
+		}
+	}
+}
+
+// Transform rewrites e bottom-up, each shared subterm once
+// fn receives a node whose children are already rewritten and returns its
+// replacement; returning (nil, false) or (n, false) keeps the node as is.
+// Example: replace every Mod by a fresh variable and collect side constraints.
+func Transform(e *Expr, fn func(*Expr) (*Expr, bool)) *Expr {
+	ctx := e.ctx
+	done := make(map[C.uint]*Expr)
+
+	type frame struct {
+		n        *Expr
+		children []*Expr // set once the node has been expanded
+	}
+	stack := []frame{{n: e}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		id := C.Z3_get_ast_id(ctx.c, top.n.ast)
+		if _, ok := done[id]; ok {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		if top.children == nil {
+			// First visit: schedule the children, rebuild on the way back up
+			top.children = top.n.children()
+			for i := len(top.children) - 1; i >= 0; i-- {
+				stack = append(stack, frame{n: top.children[i]})
+			}
+			continue
+		}
+		n, children := top.n, top.children
+		stack = stack[:len(stack)-1]
+
+		// Rebuild with the rewritten children, then let fn replace the node
+		changed := false
+		newArgs := make([]C.Z3_ast, len(children))
+		for i, c := range children {
+			r := done[C.Z3_get_ast_id(ctx.c, c.ast)]
+			newArgs[i] = r.ast
+			changed = changed || r.ast != c.ast
+		}
+		res := n
+		if changed {
+			res = ctx.wrap(C.Z3_update_term(ctx.c, n.ast, C.uint(len(newArgs)), &newArgs[0]))
+		}
+		if r, ok := fn(res); ok && r != nil {
+			res = r
+		}
+		done[id] = res
+	}
+
+	return done[C.Z3_get_ast_id(ctx.c, e.ast)]
+}
+
+// children returns the arguments of an application or the body of a quantifier
+// The result is never nil so that Transform can tell expanded leaves apart.
+func (e *Expr) children() []*Expr {
+	switch e.Kind() {
+	case KindApp:
+		return e.Args()
+	case KindQuantifier:
+		return []*Expr{e.ctx.wrap(C.Z3_get_quantifier_body(e.ctx.c, e.ast))}
+	}
+	return []*Expr{}
+}
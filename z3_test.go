@@ -1,7 +1,11 @@
 package z3
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"reflect"
+	"regexp"
 	"runtime"
 	"testing"
 )
@@ -251,3 +255,1375 @@ func TestOptimization(t *testing.T) {
 		t.Errorf("Expected 11 (minimum), got %s", m.Eval(x))
 	}
 }
+
+func TestStructSort(t *testing.T) {
+	type Header struct {
+		Version uint8 `z3:"bv4"`
+		Signed  int8  `z3:"bv8"`
+	}
+	type Packet struct {
+		Header Header
+		Len    uint16 `z3:"bv16"`
+		Count  int
+		Valid  bool
+		Ratio  float64
+		Debug  string `z3:"-"`
+	}
+
+	ctx := NewContext(NewConfig())
+	solver := ctx.NewSolver()
+
+	ps, err := ctx.StructSortOf(Packet{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs, err := ctx.StructSortOf(Header{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := ps.Const("p")
+	h := ps.Field(p, "Header")
+	solver.Assert(ctx.Eq(ps.Field(p, "Len"), ctx.BVVal(1500, 16)))
+	solver.Assert(ctx.Eq(ps.Field(p, "Count"), ctx.Int(-7, ctx.IntSort())))
+	solver.Assert(ps.Field(p, "Valid"))
+	solver.Assert(ctx.FPAEq(ps.Field(p, "Ratio"), ctx.FloatVal(0.5, ctx.Float64Sort())))
+	solver.Assert(ctx.Eq(hs.Field(h, "Version"), ctx.BVVal(9, 4)))
+	solver.Assert(ctx.Eq(hs.Field(h, "Signed"), ctx.BVVal(-3, 8)))
+
+	if !solver.Check() {
+		t.Fatal("Expected SAT for struct field constraints")
+	}
+
+	var got Packet
+	if err := solver.GetModel().Decode(ps, p, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := Packet{Header: Header{Version: 9, Signed: -3}, Len: 1500, Count: -7, Valid: true, Ratio: 0.5}
+	if got != want {
+		t.Errorf("Decoded %+v, want %+v", got, want)
+	}
+
+	// A concrete value must round-trip through the same sort
+	val, err := ps.Val(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solver.Assert(ctx.Eq(p, val))
+	if !solver.Check() {
+		t.Fatal("Concrete struct value should agree with the field constraints")
+	}
+	if _, err := ps.Val(nil); err == nil {
+		t.Error("Expected an error for a nil value")
+	}
+	if _, err := ps.Val((*Packet)(nil)); err == nil {
+		t.Error("Expected an error for a nil pointer")
+	}
+
+	// Special floats have no usable bit pattern in the model
+	q := ps.Const("q")
+	solver.Assert(ctx.FPAIsNaN(ps.Field(q, "Ratio")))
+	if !solver.Check() {
+		t.Fatal("Expected SAT for a NaN field")
+	}
+	if err := solver.GetModel().Decode(ps, q, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsNaN(got.Ratio) {
+		t.Errorf("Decoded Ratio %v, want NaN", got.Ratio)
+	}
+
+	if _, err := ctx.StructSortOf(struct{ C chan int }{}); err == nil {
+		t.Error("Expected an error for an unsupported field type")
+	}
+}
+
+func TestRecursiveFunction(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	solver := ctx.NewSolver()
+	intSort := ctx.IntSort()
+
+	// fib(n) = if n < 2 then n else fib(n - 1) + fib(n - 2)
+	fib := ctx.RecFuncDecl("fib", []*Sort{intSort}, intSort)
+	n := ctx.Const("n", intSort)
+	minusOne := ctx.Add(n, ctx.Int(-1, intSort))
+	minusTwo := ctx.Add(n, ctx.Int(-2, intSort))
+	ctx.AddRecDef(fib, []*Expr{n}, ctx.ITE(
+		ctx.LT(n, ctx.Int(2, intSort)),
+		n,
+		ctx.Add(ctx.Apply(fib, minusOne), ctx.Apply(fib, minusTwo)),
+	))
+
+	r := ctx.Const("r", intSort)
+	solver.Assert(ctx.Eq(r, ctx.Apply(fib, ctx.Int(10, intSort))))
+
+	if !solver.Check() {
+		t.Fatal("Expected SAT for fib(10) == r")
+	}
+	if got := solver.GetModel().Eval(r); got != "55" {
+		t.Errorf("Expected fib(10) = 55, got %s", got)
+	}
+
+	solver.Assert(ctx.Not(ctx.Eq(r, ctx.Int(55, intSort))))
+	if solver.Check() {
+		t.Fatal("fib(10) must be exactly 55")
+	}
+}
+
+func TestStringTheory(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	solver := ctx.NewSolver()
+	intSort := ctx.IntSort()
+
+	s := ctx.Const("s", ctx.StringSort())
+	port := ctx.Const("port", ctx.StringSort())
+
+	// s = "host:" ++ port, where port is 4 digits and parses to 8080
+	digits := ctx.RePlus(ctx.ReRange(ctx.StringVal("0"), ctx.StringVal("9")))
+	solver.Assert(ctx.Eq(s, ctx.Concat(ctx.StringVal("host:"), port)))
+	solver.Assert(ctx.InRe(port, digits))
+	solver.Assert(ctx.Eq(ctx.Length(port), ctx.Int(4, intSort)))
+	solver.Assert(ctx.Eq(ctx.StrToInt(port), ctx.Int(8080, intSort)))
+	solver.Assert(ctx.PrefixOf(ctx.StringVal("host"), s))
+	solver.Assert(ctx.SuffixOf(ctx.StringVal("80"), s))
+	solver.Assert(ctx.Contains(s, ctx.StringVal(":")))
+	solver.Assert(ctx.Eq(ctx.IndexOf(s, ctx.StringVal(":"), ctx.Int(0, intSort)), ctx.Int(4, intSort)))
+
+	if !solver.Check() {
+		t.Fatal("Expected SAT for the host:port constraints")
+	}
+
+	m := solver.GetModel()
+	if got, ok := m.EvalString(s); !ok || got != "host:8080" {
+		t.Errorf("Expected s = host:8080, got %q", got)
+	}
+	sub := ctx.SubString(s, ctx.Int(0, intSort), ctx.Int(4, intSort))
+	if got, _ := m.EvalString(sub); got != "host" {
+		t.Errorf("Expected substring host, got %q", got)
+	}
+	replaced := ctx.Replace(s, ctx.StringVal("host"), ctx.IntToStr(ctx.Int(127, intSort)))
+	if got, _ := m.EvalString(replaced); got != "127:8080" {
+		t.Errorf("Expected 127:8080 after replace, got %q", got)
+	}
+
+	// Non-ASCII text and backslashes are one character per rune
+	text := "naïve \\ 😀"
+	if got, _ := m.EvalString(ctx.StringVal(text)); got != text {
+		t.Errorf("Expected %q to round-trip, got %q", text, got)
+	}
+	if got := m.Eval(ctx.Length(ctx.StringVal(text))); got != "9" {
+		t.Errorf("Expected length 9, got %s", got)
+	}
+
+	// A sequence of ints is not a string and has no Go string value
+	seq := ctx.Const("seq", ctx.SeqSort(intSort))
+	if _, ok := m.EvalString(seq); ok {
+		t.Error("EvalString should reject non-string sequences")
+	}
+}
+
+func TestRegexFromGo(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	strSort := ctx.StringSort()
+
+	mustRegex := func(pattern string) *Expr {
+		re, err := ctx.RegexFromGo(pattern)
+		if err != nil {
+			t.Fatalf("RegexFromGo(%q): %v", pattern, err)
+		}
+		return re
+	}
+
+	// Two validation rules overlap: find a string accepted by both
+	emailRule := `^[a-z]+@example\.com$`
+	adminRule := `(?i)^ADMIN@`
+	solver := ctx.NewSolver()
+	s := ctx.Const("s", strSort)
+	solver.Assert(ctx.InRe(s, mustRegex(emailRule)))
+	solver.Assert(ctx.InRe(s, mustRegex(adminRule)))
+	if !solver.Check() {
+		t.Fatal("Expected the email and admin rules to overlap")
+	}
+	witness, _ := solver.GetModel().EvalString(s)
+	for _, rule := range []string{emailRule, adminRule} {
+		if !regexp.MustCompile(rule).MatchString(witness) {
+			t.Errorf("Witness %q does not match %s in Go", witness, rule)
+		}
+	}
+
+	// Disjoint rules have no common string
+	solver = ctx.NewSolver()
+	solver.Assert(ctx.InRe(s, mustRegex(`^[0-9]{3}$`)))
+	solver.Assert(ctx.InRe(s, mustRegex(`^[[:alpha:]]+$`)))
+	if solver.Check() {
+		t.Fatal("Digits and letters must not overlap")
+	}
+
+	// Bounded repetition constrains the admissible lengths
+	solver = ctx.NewSolver()
+	solver.Assert(ctx.InRe(s, mustRegex(`^(ab){2,3}$|^x$`)))
+	solver.Assert(ctx.Eq(ctx.Length(s), ctx.Int(5, ctx.IntSort())))
+	if solver.Check() {
+		t.Fatal("(ab){2,3}|x admits no string of length 5")
+	}
+
+	// Unanchored patterns match anywhere, as in regexp.MatchString
+	solver = ctx.NewSolver()
+	solver.Assert(ctx.InRe(ctx.StringVal("xx-abc-yy"), mustRegex(`a.c`)))
+	solver.Assert(ctx.Not(ctx.InRe(ctx.StringVal("a\nc"), mustRegex(`a.c`))))
+	if !solver.Check() {
+		t.Fatal("Expected unanchored a.c to match inside a longer string only")
+	}
+
+	for _, bad := range []string{`a^b`, `(?m)^a`, `\bword`, `(unclosed`, `\x{10FFFF}`} {
+		if _, err := ctx.RegexFromGo(bad); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
+
+func TestSetTheory(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	solver := ctx.NewSolver()
+	intSort := ctx.IntSort()
+	groupSort := ctx.SetSort(intSort)
+
+	// User ids 1..4, two groups and the admins derived from them
+	var users []*Expr
+	for i := 1; i <= 4; i++ {
+		users = append(users, ctx.Int(i, intSort))
+	}
+	staff := ctx.Const("staff", groupSort)
+	ops := ctx.Const("ops", groupSort)
+	admins := ctx.SetIntersect(staff, ops)
+	everyone := ctx.SetUnion(staff, ops)
+
+	solver.Assert(ctx.Eq(staff, ctx.SetAdd(ctx.SetAdd(ctx.SetAdd(ctx.EmptySet(intSort), users[0]), users[1]), users[2])))
+	solver.Assert(ctx.SetSubset(ops, ctx.SetDel(ctx.FullSet(intSort), users[0])))
+	solver.Assert(ctx.SetSubset(everyone, ctx.SetUnion(staff, ctx.SetAdd(ctx.EmptySet(intSort), users[3]))))
+	solver.Assert(ctx.SetMember(users[3], ops))
+	solver.Assert(ctx.Eq(ctx.SetCardinality(admins, users...), ctx.Int(1, intSort)))
+	solver.Assert(ctx.SetMember(users[2], ctx.SetDifference(staff, ops)))
+
+	if !solver.Check() {
+		t.Fatal("Expected SAT for the group membership constraints")
+	}
+
+	m := solver.GetModel()
+	if m.Eval(ctx.SetMember(users[1], admins)) != "true" {
+		t.Error("User 2 should be the only admin")
+	}
+	if m.Eval(ctx.SetMember(users[0], ctx.SetComplement(ops))) != "true" {
+		t.Error("User 1 must not be in ops")
+	}
+	if got := m.Eval(ctx.SetCardinality(everyone, users...)); got != "4" {
+		t.Errorf("Expected 4 users overall, got %s", got)
+	}
+}
+
+func TestAdvancedArrays(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	solver := ctx.NewSolver()
+	intSort := ctx.IntSort()
+	arrSort := ctx.ArraySort(intSort, intSort)
+	zero, one := ctx.Int(0, intSort), ctx.Int(1, intSort)
+
+	// Memory starts as all zeros, then address 3 is written
+	mem := ctx.Store(ctx.ConstArray(intSort, zero), ctx.Int(3, intSort), ctx.Int(42, intSort))
+	solver.Assert(ctx.Eq(ctx.ArrayDefault(mem), zero))
+
+	// inc[i] = mem[i] + 1 for every i
+	i := ctx.Const("i", intSort)
+	inc := ctx.Lambda([]*Expr{i}, ctx.Add(ctx.Select(mem, i), one))
+	solver.Assert(ctx.Eq(ctx.Select(inc, ctx.Int(3, intSort)), ctx.Int(43, intSort)))
+	solver.Assert(ctx.Eq(ctx.Select(inc, ctx.Int(7, intSort)), one))
+
+	// An unknown array that differs from mem only where we say so
+	a := ctx.Const("a", arrSort)
+	solver.Assert(ctx.Eq(ctx.Select(a, ctx.Int(5, intSort)), ctx.Int(9, intSort)))
+	solver.Assert(ctx.Eq(ctx.ArrayDefault(a), ctx.Int(-1, intSort)))
+
+	if !solver.Check() {
+		t.Fatal("Expected SAT for constant array and lambda constraints")
+	}
+
+	m := solver.GetModel()
+	entries, def, ok := m.EvalArray(mem)
+	if !ok || def != "0" || entries["3"] != "42" || len(entries) != 1 {
+		t.Errorf("Expected mem = {3: 42} default 0, got %v default %q", entries, def)
+	}
+	entries, def, ok = m.EvalArray(a)
+	if !ok || def != "(- 1)" || entries["5"] != "9" {
+		t.Errorf("Expected a[5] = 9 default -1, got %v default %q", entries, def)
+	}
+
+	// Theorems: map(f, b)[j] == f(b[j]) and b != c implies they differ at ext(b, c)
+	f := ctx.CreateFuncDecl("f", []*Sort{intSort}, intSort)
+	b := ctx.Const("b", arrSort)
+	c := ctx.Const("c", arrSort)
+	j := ctx.Const("j", intSort)
+	ext := ctx.ArrayExt(b, c)
+	prover := ctx.NewSolver()
+	prover.Assert(ctx.Not(ctx.And(
+		ctx.Eq(ctx.Select(ctx.ArrayMap(f, b), j), ctx.Apply(f, ctx.Select(b, j))),
+		ctx.Implies(ctx.Not(ctx.Eq(b, c)), ctx.Not(ctx.Eq(ctx.Select(b, ext), ctx.Select(c, ext)))),
+	)))
+	if prover.Check() {
+		t.Fatal("ArrayMap or ArrayExt semantics are broken")
+	}
+	// A lambda is not a finite table of entries
+	succ := ctx.Const("succ", arrSort)
+	lam := ctx.NewSolver()
+	lam.Assert(ctx.Eq(succ, ctx.Lambda([]*Expr{j}, ctx.Add(j, ctx.Int(1, intSort)))))
+	if !lam.Check() {
+		t.Fatal("Expected SAT for a lambda-defined array")
+	}
+	if _, _, ok := lam.GetModel().EvalArray(succ); ok {
+		t.Error("Expected EvalArray to report ok=false for a lambda")
+	}
+}
+
+func TestQuantifierPatterns(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	solver := ctx.NewSolver()
+	intSort := ctx.IntSort()
+
+	// f is the left inverse of g: forall x. f(g(x)) == x, triggered on g(x)
+	f := ctx.CreateFuncDecl("f", []*Sort{intSort}, intSort)
+	g := ctx.CreateFuncDecl("g", []*Sort{intSort}, intSort)
+	x := ctx.Const("x", intSort)
+	gx := ctx.Apply(g, x)
+	solver.Assert(ctx.ForallWith([]*Expr{x}, ctx.Eq(ctx.Apply(f, gx), x), QuantifierOptions{
+		Weight:   1,
+		Patterns: [][]*Expr{{gx}},
+		QID:      "f_inverts_g",
+		SkolemID: "sk_f",
+	}))
+
+	a := ctx.Const("a", intSort)
+	solver.Assert(ctx.Not(ctx.Eq(ctx.Apply(f, ctx.Apply(g, a)), a)))
+
+	if solver.Check() {
+		t.Fatal("The pattern g(x) should instantiate the axiom with a")
+	}
+
+	// Exists with options still behaves like Exists
+	solver = ctx.NewSolver()
+	y := ctx.Const("y", intSort)
+	solver.Assert(ctx.ExistsWith([]*Expr{y}, ctx.GT(y, ctx.Int(3, intSort)), QuantifierOptions{QID: "some_y"}))
+	if !solver.Check() {
+		t.Fatal("Expected SAT for exists y. y > 3")
+	}
+}
+
+func TestQuantifierElimination(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	intSort := ctx.IntSort()
+	x := ctx.Const("x", intSort)
+	y := ctx.Const("y", intSort)
+
+	// Weakest precondition on x for: exists y. x < y < 5
+	body := ctx.And(ctx.LT(x, y), ctx.LT(y, ctx.Int(5, intSort)))
+	pre, err := ctx.EliminateQuantifiers(ctx.Exists([]*Expr{y}, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	solver := ctx.NewSolver()
+	solver.Assert(ctx.Not(ctx.Eq(pre, ctx.LT(x, ctx.Int(4, intSort)))))
+	if solver.Check() {
+		t.Fatal("Expected the eliminated formula to be equivalent to x < 4")
+	}
+
+	// Model-based projection keeps the current model and drops y
+	solver = ctx.NewSolver()
+	solver.Assert(body)
+	solver.Assert(ctx.Eq(x, ctx.Int(0, intSort)))
+	if !solver.Check() {
+		t.Fatal("Expected SAT for x = 0 and x < y < 5")
+	}
+	m := solver.GetModel()
+	proj, err := m.Project([]*Expr{y}, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Eval(proj) != "true" {
+		t.Error("The projection must hold in the model it was computed from")
+	}
+
+	// proj must imply the existential it under-approximates
+	solver = ctx.NewSolver()
+	solver.Assert(proj)
+	solver.Assert(ctx.Not(pre))
+	if solver.Check() {
+		t.Error("The projection must imply exists y. x < y < 5")
+	}
+	// Quantifiers under uninterpreted functions cannot be eliminated
+	f := ctx.CreateFuncDecl("f", []*Sort{intSort}, intSort)
+	stuck := ctx.Exists([]*Expr{x}, ctx.And(
+		ctx.GT(ctx.Apply(f, x), y),
+		ctx.LT(ctx.Apply(f, ctx.Apply(f, x)), y),
+	))
+	if res, err := ctx.EliminateQuantifiers(stuck); err == nil {
+		t.Errorf("Expected an error instead of the still quantified %s", res)
+	}
+}
+
+func TestPseudoBoolean(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	solver := ctx.NewSolver()
+
+	// Five shifts: work at most 3 and at least 2 of them
+	shifts := make([]*Expr, 5)
+	for i := range shifts {
+		shifts[i] = ctx.Const(fmt.Sprintf("shift%d", i), ctx.BoolSort())
+	}
+	solver.Assert(ctx.AtMost(3, shifts...))
+	solver.Assert(ctx.AtLeast(2, shifts...))
+
+	// Night shifts (0 and 4) count double towards a fatigue budget of 3
+	fatigue := []int{2, 1, 1, 1, 2}
+	solver.Assert(ctx.PbLe(fatigue, 3, shifts...))
+	solver.Assert(ctx.PbGe([]int{1, 1}, 1, shifts[0], shifts[4]))
+	solver.Assert(ctx.PbEq([]int{1, 1, 1}, 1, shifts[1], shifts[2], shifts[3]))
+
+	if !solver.Check() {
+		t.Fatal("Expected a schedule within the shift limits")
+	}
+
+	m := solver.GetModel()
+	worked, load := 0, 0
+	for i, s := range shifts {
+		if m.Eval(s) == "true" {
+			worked++
+			load += fatigue[i]
+		}
+	}
+	if worked != 2 || load != 3 {
+		t.Errorf("Expected 2 shifts with fatigue 3, got %d shifts with fatigue %d", worked, load)
+	}
+
+	solver.Assert(ctx.AtLeast(4, shifts...))
+	if solver.Check() {
+		t.Fatal("AtLeast 4 must contradict AtMost 3")
+	}
+}
+
+func TestOptimizeSoftConstraints(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	opt := ctx.NewOptimize()
+	intSort := ctx.IntSort()
+	x := ctx.Const("x", intSort)
+
+	// Hard: 0 <= x < 10. Preferences pull x in incompatible directions.
+	opt.Assert(ctx.GT(x, ctx.Int(-1, intSort)))
+	opt.Assert(ctx.LT(x, ctx.Int(10, intSort)))
+	low := opt.AssertSoft(ctx.LT(x, ctx.Int(3, intSort)), "1", "comfort")
+	opt.AssertSoft(ctx.Eq(x, ctx.Int(0, intSort)), "2", "comfort")
+	high := opt.AssertSoft(ctx.GT(x, ctx.Int(7, intSort)), "5", "budget")
+
+	if !opt.Check() {
+		t.Fatal("Optimizer failed to find a solution")
+	}
+
+	// Groups are optimized in order: comfort is fully satisfied first (x = 0),
+	// which leaves the whole budget group violated
+	if got := opt.GetModel().Eval(x); got != "0" {
+		t.Errorf("Expected x = 0, got %s", got)
+	}
+	if got := low.Penalty(); got != "0" {
+		t.Errorf("Expected comfort penalty 0, got %s", got)
+	}
+	if got := high.Penalty(); got != "5" {
+		t.Errorf("Expected budget penalty 5, got %s", got)
+	}
+}
+
+func TestOptimizeObjectives(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	intSort := ctx.IntSort()
+	x := ctx.Const("x", intSort)
+	y := ctx.Const("y", intSort)
+	budget := func(opt *Optimize) {
+		// x, y >= 0 and x + y <= 4
+		opt.Assert(ctx.GT(x, ctx.Int(-1, intSort)))
+		opt.Assert(ctx.GT(y, ctx.Int(-1, intSort)))
+		opt.Assert(ctx.LT(ctx.Add(x, y), ctx.Int(5, intSort)))
+	}
+
+	// Box: each objective gets its own optimum
+	opt := ctx.NewOptimize()
+	budget(opt)
+	opt.SetPriority(PriorityBox)
+	maxX := opt.Maximize(x)
+	minY := opt.Minimize(y)
+	if !opt.Check() {
+		t.Fatal("Optimizer failed to find a solution")
+	}
+	if maxX.Value() != "4" || maxX.Lower() != "4" || maxX.Upper() != "4" {
+		t.Errorf("Expected max x = 4, got [%s, %s]", maxX.Lower(), maxX.Upper())
+	}
+	if minY.Value() != "0" {
+		t.Errorf("Expected min y = 0, got %s", minY.Value())
+	}
+
+	// Pareto: maximizing both x and y walks the x + y = 4 front
+	opt = ctx.NewOptimize()
+	budget(opt)
+	opt.SetPriority(PriorityPareto)
+	opt.Maximize(x)
+	opt.Maximize(y)
+	points := map[string]bool{}
+	for opt.Check() {
+		m := opt.GetModel()
+		points[m.Eval(x)+","+m.Eval(y)] = true
+		if len(points) > 5 {
+			break
+		}
+	}
+	if len(points) != 5 || !points["0,4"] || !points["4,0"] {
+		t.Errorf("Expected the 5 points of x + y = 4, got %v", points)
+	}
+}
+
+func TestOptimizeIncremental(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	intSort := ctx.IntSort()
+	opt := ctx.NewOptimize()
+	x := ctx.Const("x", intSort)
+
+	opt.Assert(ctx.LT(x, ctx.Int(100, intSort)))
+	cost := opt.Maximize(x)
+
+	// What-if: x must also stay below 10
+	opt.Push()
+	opt.Assert(ctx.LT(x, ctx.Int(10, intSort)))
+	if !opt.Check() || cost.Value() != "9" {
+		t.Fatalf("Expected max x = 9 inside the scope, got %s", cost.Value())
+	}
+	opt.Pop()
+	if !opt.Check() || cost.Value() != "99" {
+		t.Fatalf("Expected max x = 99 after Pop, got %s", cost.Value())
+	}
+
+	// Conflicting assumptions produce an unsat core
+	big := ctx.Const("big", ctx.BoolSort())
+	small := ctx.Const("small", ctx.BoolSort())
+	opt.Assert(ctx.Implies(big, ctx.GT(x, ctx.Int(50, intSort))))
+	opt.Assert(ctx.Implies(small, ctx.LT(x, ctx.Int(5, intSort))))
+	if opt.Check(big, small) {
+		t.Fatal("Expected UNSAT when assuming both big and small")
+	}
+	if core := opt.UnsatCore(); len(core) != 2 {
+		t.Errorf("Expected both assumptions in the core, got %d", len(core))
+	}
+	if !opt.Check(small) || cost.Value() != "4" {
+		t.Errorf("Expected max x = 4 assuming small, got %s", cost.Value())
+	}
+	if len(opt.Statistics()) == 0 {
+		t.Error("Expected statistics after Check")
+	}
+
+	// Export and reload into a fresh optimizer
+	copied := ctx.NewOptimize()
+	if err := copied.FromString(opt.String()); err != nil {
+		t.Fatal(err)
+	}
+	if !copied.Check() {
+		t.Fatal("Reloaded optimizer should be SAT")
+	}
+	if err := copied.FromString("(assert (> undeclared 0))"); err == nil {
+		t.Error("Expected an error for an undeclared constant")
+	}
+}
+
+func TestTacticsAndProbes(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	intSort := ctx.IntSort()
+	x := ctx.Const("x", intSort)
+	y := ctx.Const("y", intSort)
+
+	names := ctx.TacticNames()
+	found := false
+	for _, name := range names {
+		found = found || name == "solve-eqs"
+	}
+	if descr, err := ctx.TacticDescription("solve-eqs"); !found || err != nil || descr == "" {
+		t.Fatalf("Expected solve-eqs among %d tactics", len(names))
+	}
+	if _, err := ctx.TacticDescription("no-such-tactic"); err == nil {
+		t.Error("Expected an error describing an unknown tactic")
+	}
+
+	simplify, err := ctx.NewTactic("simplify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	solveEqs, err := ctx.NewTactic("solve-eqs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctx.NewTactic("no-such-tactic"); err == nil {
+		t.Error("Expected an error for an unknown tactic")
+	}
+
+	// x == y + 1 and y > 2: solve-eqs eliminates x, leaving one formula on y
+	g := ctx.NewGoal(true, false, false)
+	g.Assert(ctx.Eq(x, ctx.Add(y, ctx.Int(1, intSort))), ctx.GT(y, ctx.Int(2, intSort)))
+	res, err := simplify.AndThen(solveEqs).Repeat(3).Apply(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.NumSubgoals() != 1 || res.Subgoal(0).Size() != 1 {
+		t.Fatalf("Expected a single subgoal with one formula, got %s", res)
+	}
+	mustPanic(t, "subgoal past the end", func() { res.Subgoal(1) })
+
+	// Probes: branch on the number of constants
+	numConsts, err := ctx.NewProbe("num-consts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := numConsts.Apply(g); got != 2 {
+		t.Errorf("Expected 2 constants, got %v", got)
+	}
+	if numConsts.Gt(ctx.ProbeConst(1)).And(numConsts.Le(ctx.ProbeConst(2))).Apply(g) != 1 {
+		t.Error("Expected 1 < num-consts <= 2 to hold")
+	}
+
+	fail, _ := ctx.NewTactic("fail")
+	smt, _ := ctx.NewTactic("smt")
+	strategy := ctx.Cond(numConsts.Lt(ctx.ProbeConst(10)), fail.OrElse(smt), fail).TryFor(5000)
+	solver := ctx.ParOr(strategy, smt).Solver()
+	solver.Assert(g.AsExpr())
+	if !solver.Check() {
+		t.Fatal("Tactic-based solver should find x = y + 1, y > 2 satisfiable")
+	}
+
+	if _, err := fail.Apply(g); err == nil {
+		t.Error("Expected the fail tactic to report an error")
+	}
+}
+
+func TestSolverConstructors(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	bv8 := ctx.BVSort(8)
+	x := ctx.Const("x", bv8)
+	y := ctx.Const("y", bv8)
+
+	var pipeline []*Tactic
+	for _, name := range []string{"simplify", "bit-blast", "sat"} {
+		tactic, err := ctx.NewTactic(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pipeline = append(pipeline, tactic)
+	}
+
+	solvers := map[string]*Solver{
+		"logic":  ctx.NewSolverForLogic("QF_BV"),
+		"simple": ctx.NewSimpleSolver(),
+		"tactic": ctx.NewSolverFromTactic(pipeline[0].AndThen(pipeline[1:]...)),
+	}
+	for name, solver := range solvers {
+		// x + y == 3 with x > y and y > 0 has the unique solution x = 2, y = 1
+		solver.Assert(ctx.Eq(ctx.BVAdd(x, y), ctx.BVVal(3, 8)))
+		solver.Assert(ctx.BVUlt(y, x))
+		solver.Assert(ctx.BVUlt(ctx.BVVal(0, 8), y))
+		solver.Assert(ctx.BVUlt(x, ctx.BVVal(3, 8)))
+		if !solver.Check() {
+			t.Fatalf("%s solver: expected SAT", name)
+		}
+		if m := solver.GetModel(); m.Eval(x) != "#x02" || m.Eval(y) != "#x01" {
+			t.Errorf("%s solver: expected x = 2, y = 1, got %s, %s", name, m.Eval(x), m.Eval(y))
+		}
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	intSort := ctx.IntSort()
+	x := ctx.Const("x", intSort)
+
+	sum := ctx.Add(x, ctx.Int(1, intSort), ctx.Int(2, intSort))
+	if got := sum.Simplify().String(); got != "(+ 3 x)" {
+		t.Errorf("Expected (+ 3 x), got %s", got)
+	}
+	if got := ctx.Mul(ctx.Int(6, intSort), ctx.Int(7, intSort)).Simplify().String(); got != "42" {
+		t.Errorf("Expected 42, got %s", got)
+	}
+	bv := ctx.BVAdd(ctx.BVVal(250, 8), ctx.BVVal(10, 8))
+	if got := bv.Simplify().String(); got != "#x04" {
+		t.Errorf("Expected 8-bit wraparound to #x04, got %s", got)
+	}
+
+	// arith_lhs moves constants to the right-hand side of comparisons
+	cmp := ctx.GT(sum, ctx.Int(10, intSort))
+	res, err := cmp.SimplifyWith(ctx.NewParams().SetBool("arith_lhs", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.String(); got != "(not (<= x 7))" {
+		t.Errorf("Expected (not (<= x 7)), got %s", got)
+	}
+
+	if _, err := cmp.SimplifyWith(ctx.NewParams().SetBool("no_such_option", true)); err == nil {
+		t.Error("Expected an error for an unknown simplifier option")
+	}
+	if ctx.SimplifierHelp() == "" {
+		t.Error("Expected simplifier help text")
+	}
+}
+
+func TestSubstitution(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	intSort := ctx.IntSort()
+	one := ctx.Int(1, intSort)
+	x0 := ctx.Const("x0", intSort)
+	x1 := ctx.Const("x1", intSort)
+	x2 := ctx.Const("x2", intSort)
+
+	// SSA-style unrolling of x := x * x + 1
+	step := ctx.Add(ctx.Mul(x0, x0), one)
+	unrolled := step.Substitute([]*Expr{x0}, []*Expr{step})
+	if got := unrolled.String(); got != "(+ (* (+ (* x0 x0) 1) (+ (* x0 x0) 1)) 1)" {
+		t.Errorf("Unexpected unrolling %s", got)
+	}
+	renamed := step.Substitute([]*Expr{x0}, []*Expr{x1})
+	if got := renamed.String(); got != "(+ (* x1 x1) 1)" {
+		t.Errorf("Expected x0 renamed to x1, got %s", got)
+	}
+
+	// Template with bound variables #0 and #1
+	tmpl := ctx.GT(ctx.BoundVar(0, intSort), ctx.BoundVar(1, intSort))
+	if got := tmpl.SubstituteVars([]*Expr{x1, x2}).String(); got != "(> x1 x2)" {
+		t.Errorf("Expected (> x1 x2), got %s", got)
+	}
+
+	// Inline f(v) := v + 1 everywhere, including under a quantifier
+	f := ctx.CreateFuncDecl("f", []*Sort{intSort}, intSort)
+	body := ctx.Add(ctx.BoundVar(0, intSort), one)
+	y := ctx.Const("y", intSort)
+	e := ctx.And(
+		ctx.Eq(ctx.Apply(f, ctx.Apply(f, x0)), x2),
+		ctx.Forall([]*Expr{y}, ctx.GT(ctx.Apply(f, y), y)),
+	)
+	inlined := e.SubstituteFuncs([]*FuncDecl{f}, []*Expr{body})
+
+	solver := ctx.NewSolver()
+	solver.Assert(inlined)
+	solver.Assert(ctx.Eq(x0, ctx.Int(5, intSort)))
+	if !solver.Check() {
+		t.Fatal("Expected SAT after inlining f")
+	}
+	if got := solver.GetModel().Eval(x2); got != "7" {
+		t.Errorf("Expected f(f(5)) = 7, got %s", got)
+	}
+}
+
+func TestASTIntrospection(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	intSort := ctx.IntSort()
+	x := ctx.Const("x", intSort)
+	ten := ctx.Int(10, intSort)
+	f := ctx.CreateFuncDecl("f", []*Sort{intSort, ctx.BoolSort()}, intSort)
+
+	sum := ctx.Add(x, ten)
+	if sum.Kind() != KindApp || sum.Decl().Kind() != OpAdd || sum.NumArgs() != 2 {
+		t.Fatalf("Expected an addition with 2 arguments, got %s", sum)
+	}
+	if sum.Arg(0).Decl().Name() != "x" || !sum.Arg(0).IsConst() || sum.Arg(0).IsNumeral() {
+		t.Errorf("Expected the first argument to be the constant x, got %s", sum.Arg(0))
+	}
+	if !sum.Arg(1).IsNumeral() || sum.Arg(1).Kind() != KindNumeral || sum.Arg(1).Decl().Kind() != OpArithNum {
+		t.Errorf("Expected the second argument to be a numeral, got %s", sum.Arg(1))
+	}
+	if len(sum.Args()) != 2 {
+		t.Errorf("Expected 2 args, got %d", len(sum.Args()))
+	}
+
+	// Sorts flow through: a constant with the sort of sum can be compared to it
+	y := ctx.Const("y", sum.Sort())
+	if cmp := ctx.LT(y, sum); cmp.Decl().Kind() != OpLT || !cmp.Decl().Range().Equal(ctx.BoolSort()) {
+		t.Errorf("Expected a Bool-valued comparison, got %s", cmp)
+	}
+
+	app := ctx.Apply(f, x, ctx.Not(ctx.Const("p", ctx.BoolSort())))
+	decl := app.Decl()
+	if decl.Kind() != OpUninterpreted || decl.Name() != "f" || decl.Arity() != 2 {
+		t.Errorf("Expected uninterpreted f/2, got %s", decl)
+	}
+	if !decl.Domain(1).Equal(ctx.BoolSort()) || !decl.Range().Equal(intSort) {
+		t.Errorf("Unexpected signature %s", decl)
+	}
+	if app.Arg(1).Decl().Kind() != OpNot {
+		t.Errorf("Expected a negation, got %s", app.Arg(1))
+	}
+
+	tt := ctx.Eq(x, x).Simplify()
+	if !tt.IsTrue() || tt.IsFalse() {
+		t.Errorf("Expected x == x to simplify to true, got %s", tt)
+	}
+	if !ctx.Not(tt).Simplify().IsFalse() {
+		t.Error("Expected not true to simplify to false")
+	}
+
+	q := ctx.Forall([]*Expr{x}, ctx.GT(x, ten))
+	if q.Kind() != KindQuantifier || q.Decl() != nil || q.NumArgs() != 0 {
+		t.Errorf("Expected a quantifier node, got %s", q)
+	}
+	if ctx.BoundVar(0, intSort).Kind() != KindVar {
+		t.Error("Expected a bound variable node")
+	}
+}
+
+func TestWalkAndTransform(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	intSort := ctx.IntSort()
+	x := ctx.Const("x", intSort)
+
+	// e = x doubled 100 times: a DAG of ~100 nodes but a tree of 2^100
+	e := x
+	for i := 0; i < 100; i++ {
+		e = ctx.Add(e, e)
+	}
+	visited := 0
+	Walk(e, func(n *Expr) bool {
+		visited++
+		return true
+	})
+	if visited != 101 {
+		t.Errorf("Expected 101 distinct nodes, visited %d", visited)
+	}
+
+	// Pruning: stop at the root
+	visited = 0
+	Walk(e, func(n *Expr) bool {
+		visited++
+		return false
+	})
+	if visited != 1 {
+		t.Errorf("Expected only the root to be visited, got %d", visited)
+	}
+
+	// Replace every Mod by an auxiliary variable r with 0 <= r < 7 and x - r divisible by 7
+	seven := ctx.Int(7, intSort)
+	y := ctx.Const("y", intSort)
+	formula := ctx.And(
+		ctx.Eq(ctx.Mod(x, seven), ctx.Int(3, intSort)),
+		ctx.Forall([]*Expr{y}, ctx.Implies(ctx.GT(y, x), ctx.GT(y, ctx.Mod(x, seven)))),
+	)
+	var side []*Expr
+	aux := 0
+	rewritten := Transform(formula, func(n *Expr) (*Expr, bool) {
+		if n.Decl() == nil || n.Decl().Kind() != OpMod {
+			return nil, false
+		}
+		aux++
+		r := ctx.Const(fmt.Sprintf("r%d", aux), intSort)
+		q := ctx.Const(fmt.Sprintf("q%d", aux), intSort)
+		side = append(side,
+			ctx.Eq(n.Arg(0), ctx.Add(ctx.Mul(q, seven), r)),
+			ctx.GT(r, ctx.Int(-1, intSort)),
+			ctx.LT(r, seven))
+		return r, true
+	})
+	if aux != 1 {
+		t.Errorf("Expected the shared x mod 7 to be rewritten once, got %d", aux)
+	}
+	Walk(rewritten, func(n *Expr) bool {
+		if n.Decl() != nil && n.Decl().Kind() == OpMod {
+			t.Errorf("Mod left in %s", rewritten)
+		}
+		return true
+	})
+
+	solver := ctx.NewSolver()
+	solver.Assert(rewritten)
+	for _, c := range side {
+		solver.Assert(c)
+	}
+	solver.Assert(ctx.Eq(x, ctx.Int(10, intSort)))
+	if !solver.Check() {
+		t.Fatal("Expected x = 10 to satisfy x mod 7 == 3 after the rewrite")
+	}
+
+	// Returning false keeps the term identical
+	if same := Transform(formula, func(n *Expr) (*Expr, bool) { return nil, false }); same.String() != formula.String() {
+		t.Errorf("Identity transform changed %s into %s", formula, same)
+	}
+}
+
+func TestExprIdentity(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	intSort := ctx.IntSort()
+	x := ctx.Const("x", intSort)
+
+	// Two separately built copies of the same term
+	a := ctx.GT(ctx.Add(x, ctx.Int(1, intSort)), ctx.Int(5, intSort))
+	b := ctx.GT(ctx.Add(ctx.Const("x", intSort), ctx.Int(1, intSort)), ctx.Int(5, intSort))
+	c := ctx.GT(ctx.Add(x, ctx.Int(2, intSort)), ctx.Int(5, intSort))
+
+	if a == b || !a.Equal(b) || a.ID() != b.ID() || a.Hash() != b.Hash() {
+		t.Fatal("Expected distinct Go values for the same term to compare equal")
+	}
+	if a.Equal(c) || a.ID() == c.ID() {
+		t.Fatal("Expected different terms to compare unequal")
+	}
+
+	var set ExprSet
+	for _, e := range []*Expr{a, b, c, a} {
+		set.Add(e)
+	}
+	if set.Len() != 2 || !set.Contains(b) || len(set.Items()) != 2 {
+		t.Errorf("Expected 2 distinct constraints, got %d", set.Len())
+	}
+	set.Remove(b)
+	if set.Contains(a) || set.Len() != 1 {
+		t.Error("Removing b should remove the equal term a")
+	}
+
+	var cache ExprMap[string]
+	cache.Set(a, "first")
+	cache.Set(b, "second")
+	cache.Set(c, "other")
+	if v, ok := cache.Get(a); !ok || v != "second" || cache.Len() != 2 {
+		t.Errorf("Expected b to overwrite a, got %q (len %d)", v, cache.Len())
+	}
+	cache.Delete(c)
+	if _, ok := cache.Get(c); ok {
+		t.Error("Expected c to be deleted")
+	}
+	n := 0
+	cache.Range(func(k *Expr, v string) bool {
+		n++
+		return true
+	})
+	if n != 1 {
+		t.Errorf("Expected one entry in Range, got %d", n)
+	}
+}
+
+func TestSortIntrospection(t *testing.T) {
+	ctx := NewContext(NewConfig())
+
+	bv := ctx.BVSort(12)
+	if bv.Kind() != SortBV || bv.BVSize() != 12 {
+		t.Errorf("Expected a 12-bit BV sort, got %s", bv)
+	}
+
+	fp := ctx.Float32Sort()
+	if fp.Kind() != SortFP || fp.FPEbits() != 8 || fp.FPSbits() != 24 {
+		t.Errorf("Expected Float32 to have 8/24 bits, got %d/%d", fp.FPEbits(), fp.FPSbits())
+	}
+
+	arr := ctx.ArraySort(ctx.IntSort(), ctx.BoolSort())
+	if arr.Kind() != SortArray || !arr.ArrayDomain().Equal(ctx.IntSort()) || !arr.ArrayRange().Equal(ctx.BoolSort()) {
+		t.Errorf("Unexpected array sort %s", arr)
+	}
+	if !ctx.SetSort(ctx.IntSort()).Equal(arr) {
+		t.Error("Expected a set of Int to be an array from Int to Bool")
+	}
+
+	user := ctx.CreateSort("Color")
+	if user.Kind() != SortUninterpreted || user.Name() != "Color" {
+		t.Errorf("Expected uninterpreted sort Color, got %s", user.Name())
+	}
+	if ctx.IntSort().Name() != "Int" || ctx.IntSort().Equal(ctx.BoolSort()) {
+		t.Error("Int sort confused with Bool")
+	}
+
+	x := ctx.Const("x", ctx.StringSort())
+	if x.Sort().Kind() != SortSeq {
+		t.Errorf("Expected strings to be sequences, got kind %d", x.Sort().Kind())
+	}
+}
+
+func TestTypedExpressions(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	s := ctx.NewSolver()
+
+	x, y := ctx.IntConst("x"), ctx.IntConst("y")
+	r := ctx.RealConst("r")
+	b := ctx.BVConst("b", 8)
+	f := ctx.FloatConst("f", ctx.Float32Sort())
+	arr := ArrayConst[Int, BV](ctx, "arr", ctx.IntSort(), ctx.BVSort(8))
+
+	s.Assert(x.Add(y).Eq(ctx.IntLit(10)).
+		And(x.Gt(y), y.Ge(ctx.IntLit(3))).Expr())
+	s.Assert(r.Mul(ctx.RealLit(2, 1)).Eq(x.ToReal()).Expr())
+	s.Assert(b.SLt(ctx.BVLit(0, 8)).And(b.UGt(ctx.BVLit(200, 8))).Expr())
+	s.Assert(arr.Store(x, b).Select(x).Eq(b).Expr())
+	s.Assert(f.Add(ctx.FloatLit(1.5, ctx.Float32Sort())).Eq(ctx.FloatLit(4, ctx.Float32Sort())).Expr())
+	s.Assert(If(x.Lt(ctx.IntLit(7)), ctx.IntLit(1), ctx.IntLit(2)).Eq(ctx.IntLit(1)).Expr())
+
+	if !s.Check() {
+		t.Fatal("Expected typed constraints to be satisfiable")
+	}
+	m := s.GetModel()
+	if m.Eval(x.Expr()) != "6" || m.Eval(r.Expr()) != "3.0" {
+		t.Errorf("Expected x=6, r=3.0, got x=%s, r=%s", m.Eval(x.Expr()), m.Eval(r.Expr()))
+	}
+
+	if _, err := As[Int](ctx.BoolLit(true).Expr()); err == nil {
+		t.Error("Expected a Bool expression to be rejected as Int")
+	}
+	if _, err := As[Array[Int, BV]](arr.Expr()); err != nil {
+		t.Errorf("Expected the array to round-trip: %v", err)
+	}
+	if _, err := As[Array[Int, Int]](arr.Expr()); err == nil {
+		t.Error("Expected an Int->BV array to be rejected as Int->Int")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic on mismatched bit-vector widths")
+		}
+	}()
+	b.Add(ctx.BVConst("c", 16))
+}
+
+func TestFluentOperators(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	solver := ctx.NewSolver()
+
+	intSort := ctx.IntSort()
+	x := ctx.Const("x", intSort)
+	y := ctx.Const("y", intSort)
+	ten := ctx.Int(10, intSort)
+	twenty := ctx.Int(20, intSort)
+
+	// x + 10 > 20 && y < x && (y >= 5 => x == 12)
+	solver.Assert(x.Add(ten).Gt(twenty).And(y.Lt(x), y.Ge(ctx.Int(5, intSort)).Implies(x.Eq(ctx.Int(12, intSort)))))
+	solver.Assert(y.Mul(ctx.Int(2, intSort)).Eq(ctx.Int(10, intSort)))
+
+	// 0xFF is -1 signed but 255 unsigned
+	b := ctx.Const("b", ctx.BVSort(8))
+	solver.Assert(b.Eq(ctx.BVVal(0xFF, 8)))
+	solver.Assert(b.Lt(ctx.BVVal(0, 8)))
+	solver.Assert(b.Gt(ctx.BVVal(200, 8), Unsigned))
+
+	f := ctx.Const("f", ctx.Float64Sort())
+	solver.Assert(f.Add(ctx.FloatVal(0.5, ctx.Float64Sort())).Gt(ctx.FloatVal(2, ctx.Float64Sort())))
+
+	arr := ctx.Const("arr", ctx.ArraySort(intSort, intSort))
+	solver.Assert(arr.Store(x, y).Select(x).Eq(y))
+
+	if !solver.Check() {
+		t.Fatal("Expected the fluent constraints to be satisfiable")
+	}
+	m := solver.GetModel()
+	if m.Eval(x) != "12" || m.Eval(y) != "5" {
+		t.Errorf("Expected x=12, y=5, got x=%s, y=%s", m.Eval(x), m.Eval(y))
+	}
+
+	if kind := b.Gt(b, Unsigned).Decl().Kind(); kind != OpBVUgt {
+		t.Errorf("Expected an unsigned comparison, got kind %d", kind)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Add on Bool operands to panic")
+		}
+	}()
+	ctx.BoolLit(true).Expr().Add(ctx.BoolLit(false).Expr())
+}
+
+func TestFreshConstants(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	intSort := ctx.IntSort()
+
+	a := ctx.FreshConst("aux", intSort)
+	b := ctx.FreshConst("aux", intSort)
+	if a.Equal(b) {
+		t.Fatal("Expected fresh constants with the same prefix to differ")
+	}
+	if name := a.Decl().Name(); len(name) < 3 || name[:3] != "aux" {
+		t.Errorf("Expected the fresh name to keep its prefix, got %q", name)
+	}
+
+	// A fresh constant never captures a user variable of the same name
+	user := ctx.Const(a.Decl().Name(), intSort)
+	if user.Equal(a) {
+		t.Error("Expected a user constant not to alias a fresh one")
+	}
+
+	f := ctx.FreshFuncDecl("f", []*Sort{intSort}, intSort)
+	g := ctx.FreshFuncDecl("f", []*Sort{intSort}, intSort)
+	if ctx.Apply(f, a).Equal(ctx.Apply(g, a)) {
+		t.Error("Expected fresh functions with the same prefix to differ")
+	}
+
+	k7 := ctx.ConstInt(7, intSort)
+	if !k7.Equal(ctx.ConstInt(7, intSort)) || k7.Equal(ctx.ConstInt(8, intSort)) {
+		t.Error("Expected integer-named constants to be identified by their index")
+	}
+	mustPanic(t, "an index of 2^30", func() { ctx.ConstInt(1<<30, intSort) })
+
+	solver := ctx.NewSolver()
+	solver.Assert(ctx.Eq(k7, ctx.Int(3, intSort)))
+	solver.Assert(a.Gt(k7))
+	solver.Assert(b.Lt(k7))
+	if !solver.Check() {
+		t.Fatal("Expected fresh constants to be independent")
+	}
+	t.Logf("Fresh constants: %s, %s, %s", a, b, k7)
+}
+
+func TestProofs(t *testing.T) {
+	cfg := NewConfig()
+	cfg.SetProofs(true)
+	ctx := NewContext(cfg)
+	cfg.Close()
+
+	intSort := ctx.IntSort()
+	x := ctx.Const("x", intSort)
+	solver := ctx.NewSolver()
+	solver.Assert(x.Gt(ctx.Int(5, intSort)))
+	solver.Assert(x.Lt(ctx.Int(3, intSort)))
+
+	if solver.Check() {
+		t.Fatal("Expected x > 5 && x < 3 to be UNSAT")
+	}
+	pr := solver.Proof()
+	if pr == nil {
+		t.Fatal("Expected a proof after an UNSAT check")
+	}
+
+	proof, err := NewProof(pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proof.Root().Conclusion.IsFalse() {
+		t.Errorf("Expected the proof to conclude false, got %s", proof.Root().Conclusion)
+	}
+
+	asserted := 0
+	proof.Walk(func(s *ProofStep) {
+		if !s.Rule.IsProofRule() {
+			t.Errorf("Unexpected rule kind %d", s.Rule)
+		}
+		for _, p := range s.Premises {
+			if p.ID >= s.ID {
+				t.Errorf("Premise %d listed after step %d", p.ID, s.ID)
+			}
+		}
+		if s.Rule == OpPrAsserted {
+			asserted++
+		}
+	})
+	if asserted != 2 {
+		t.Errorf("Expected both assertions to be used, got %d", asserted)
+	}
+
+	if dot := proof.DOT(); !regexp.MustCompile(`n\d+ -> n\d+`).MatchString(dot) {
+		t.Errorf("Expected premise edges in DOT output:\n%s", dot)
+	}
+	data, err := json.Marshal(proof)
+	if err != nil || !regexp.MustCompile(`"rule":"asserted"`).Match(data) {
+		t.Errorf("Unexpected JSON output %s (%v)", data, err)
+	}
+
+	if _, err := NewProof(x); err == nil {
+		t.Error("Expected a non-proof term to be rejected")
+	}
+
+	// Without proofs enabled there is nothing to return
+	plain := NewContext(NewConfig())
+	s2 := plain.NewSolver()
+	s2.Assert(plain.BoolLit(false).Expr())
+	if s2.Check() || s2.Proof() != nil {
+		t.Error("Expected no proof when proofs are disabled")
+	}
+}
+
+func TestOnClause(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	solver := ctx.NewSolver()
+
+	clauses := 0
+	err := solver.OnClause(func(proofHint *Expr, deps []uint, literals []*Expr) {
+		clauses++
+	})
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// Pigeonhole: 4 pigeons do not fit into 3 holes
+	const pigeons, holes = 4, 3
+	var in [pigeons][holes]*Expr
+	for p := 0; p < pigeons; p++ {
+		for h := 0; h < holes; h++ {
+			in[p][h] = ctx.Const(fmt.Sprintf("p%d_h%d", p, h), ctx.BoolSort())
+		}
+		solver.Assert(ctx.Or(in[p][:]...))
+	}
+	for h := 0; h < holes; h++ {
+		for p := 0; p < pigeons; p++ {
+			for q := p + 1; q < pigeons; q++ {
+				solver.Assert(ctx.Or(ctx.Not(in[p][h]), ctx.Not(in[q][h])))
+			}
+		}
+	}
+
+	if solver.Check() {
+		t.Fatal("Expected the pigeonhole problem to be UNSAT")
+	}
+	if clauses == 0 {
+		t.Error("Expected the callback to see clauses")
+	}
+	t.Logf("Saw %d clauses", clauses)
+}
+
+func TestStructSortSameName(t *testing.T) {
+	ctx := NewContext(NewConfig())
+
+	intHeader := func() reflect.Type {
+		type Header struct{ Len int }
+		return reflect.TypeOf(Header{})
+	}()
+	boolHeader := func() reflect.Type {
+		type Header struct{ Len bool }
+		return reflect.TypeOf(Header{})
+	}()
+
+	is, err := ctx.StructSortOf(intHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs, err := ctx.StructSortOf(boolHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anon, err := ctx.StructSortOf(struct{ Len int }{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is.Equal(bs.Sort) || is.Equal(anon.Sort) {
+		t.Fatalf("Expected distinct sorts for same-named types, got %s, %s and %s", is, bs, anon)
+	}
+
+	solver := ctx.NewSolver()
+	h := is.Const("h")
+	solver.Assert(ctx.Eq(is.Field(h, "Len"), ctx.Int(42, ctx.IntSort())))
+	if !solver.Check() {
+		t.Fatal("Expected SAT")
+	}
+	if got := solver.GetModel().Eval(h); got != "("+is.Name()+" 42)" && got != "(|"+is.Name()+"| 42)" {
+		t.Errorf("Expected the int-field Header to keep its Int field, got %s", got)
+	}
+}
+
+func TestStructValOverflow(t *testing.T) {
+	type Frame struct {
+		Kind  uint16 `z3:"bv8"`
+		Delta int32  `z3:"bv4"`
+	}
+	ctx := NewContext(NewConfig())
+	fs, err := ctx.StructSortOf(Frame{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fs.Val(Frame{Kind: 255, Delta: -8}); err != nil {
+		t.Errorf("Expected boundary values to fit: %v", err)
+	}
+	if _, err := fs.Val(Frame{Kind: 300}); err == nil {
+		t.Error("Expected 300 not to fit in bv8")
+	}
+	if _, err := fs.Val(Frame{Delta: 8}); err == nil {
+		t.Error("Expected 8 not to fit in a signed bv4")
+	}
+}
+
+func TestStringValCharacterLimit(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	solver := ctx.NewSolver()
+
+	// U+2FFFF is the last character Z3 supports
+	n := ctx.Const("n", ctx.IntSort())
+	solver.Assert(ctx.Eq(n, ctx.Length(ctx.StringVal("a\U0002FFFFb"))))
+	if !solver.Check() || solver.GetModel().Eval(n) != "3" {
+		t.Errorf("Expected length 3 for a string ending at U+2FFFF")
+	}
+
+	if _, err := ctx.NewStringVal("a\U0010FFFFb"); err == nil {
+		t.Error("Expected an error for a character beyond U+2FFFF")
+	}
+	if _, err := ctx.NewStringVal("a\xffb"); err == nil {
+		t.Error("Expected an error for invalid UTF-8")
+	}
+	mustPanic(t, "StringVal of a character beyond U+2FFFF", func() { ctx.StringVal("a\U0010FFFFb") })
+}
+
+// mustPanic fails the test unless fn panics
+func mustPanic(t *testing.T, what string, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for %s", what)
+		}
+	}()
+	fn()
+}
+
+func TestTypedFloatChecks(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	f32 := ctx.FloatConst("a", ctx.Float32Sort())
+	f64 := ctx.FloatConst("b", ctx.Float64Sort())
+
+	mustPanic(t, "Float32 + Float64", func() { f32.Add(f64) })
+	mustPanic(t, "Float32 < Float64", func() { f32.Lt(f64) })
+	mustPanic(t, "a Float of Int sort", func() { ctx.FloatConst("c", ctx.IntSort()) })
+	mustPanic(t, "a Float literal of BV sort", func() { ctx.FloatLit(1, ctx.BVSort(32)) })
+
+	// Same formats still combine
+	f32.Add(ctx.FloatLit(1, ctx.Float32Sort())).Ge(f32)
+}
+
+func TestTypedSortChecks(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	p := ctx.BoolConst("p")
+	b8 := ctx.BVConst("b8", 8)
+	b16 := ctx.BVConst("b16", 16)
+	arr := ArrayConst[BV, Bool](ctx, "arr", ctx.BVSort(8), ctx.BoolSort())
+
+	mustPanic(t, "If with BV8 and BV16 branches", func() { If(p, b8, b16) })
+	mustPanic(t, "Extract past the width", func() { b8.Extract(8, 0) })
+	mustPanic(t, "Extract with lo > hi", func() { b8.Extract(2, 3) })
+	mustPanic(t, "select with a BV16 index", func() { arr.Select(b16) })
+	mustPanic(t, "store at a BV16 index", func() { arr.Store(b16, p) })
+
+	other := ArrayConst[BV, Bool](ctx, "other", ctx.BVSort(16), ctx.BoolSort())
+	mustPanic(t, "Eq of differently indexed arrays", func() { arr.Eq(other) })
+
+	// Matching sorts still combine
+	If(p, b8, b8.Extract(7, 0)).ULt(b8)
+	arr.Store(b8, p).Select(b8).And(arr.Eq(arr))
+}
+
+func TestFluentOperandChecks(t *testing.T) {
+	ctx := NewContext(NewConfig())
+	x := ctx.Const("x", ctx.IntSort())
+	r := ctx.Const("r", ctx.RealSort())
+	b8 := ctx.Const("b8", ctx.BVSort(8))
+	b16 := ctx.Const("b16", ctx.BVSort(16))
+	f := ctx.Const("f", ctx.Float64Sort())
+	p := ctx.Const("p", ctx.BoolSort())
+	arr := ctx.Const("arr", ctx.ArraySort(ctx.IntSort(), ctx.BoolSort()))
+
+	mustPanic(t, "Int + BV", func() { x.Add(b8) })
+	mustPanic(t, "FP > Int", func() { f.Gt(x) })
+	mustPanic(t, "BV8 < BV16", func() { b8.Lt(b16, Unsigned) })
+	mustPanic(t, "Int == Bool", func() { x.Eq(p) })
+	mustPanic(t, "Bool && Int", func() { p.And(x) })
+	mustPanic(t, "Int => Bool", func() { x.Implies(p) })
+	mustPanic(t, "select with a BV index", func() { arr.Select(b8) })
+	mustPanic(t, "store of an Int value", func() { arr.Store(x, x) })
+
+	// Int and Real mix the way Z3 allows
+	if s := x.Add(r).Sort(); s.Kind() != SortReal {
+		t.Errorf("Expected Int + Real to be Real, got %s", s)
+	}
+	arr.Store(x, p).Select(x).And(x.Le(r))
+}